@@ -0,0 +1,221 @@
+// Package api checks the exported API of packages against the types of the
+// packages that it exposes.
+package api
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"github.com/flowdev/spaghetti-cutter/config"
+	"github.com/flowdev/spaghetti-cutter/data"
+	"github.com/flowdev/spaghetti-cutter/x/pkgs"
+)
+
+// Check checks that the exported API of the given package doesn't expose
+// types of packages that are forbidden for its type (`mayNotExpose` and
+// `mayNotExposePackages` of the type).
+// The exported functions, methods, types, variables and constants are
+// checked. Only the exported fields and methods of structs and interfaces
+// count and unexported types of the package itself are followed.
+// The type information of the package has to be computed already
+// (see: parse.TypeCheck). Test packages aren't checked.
+func Check(pkg *pkgs.Package, rootPkg string, cfg config.Config) (errs []error) {
+	if pkg.TypesInfo == nil || pkg.Types == nil || pkgs.IsTestPackage(pkg) {
+		return nil
+	}
+	tds := cfg.AllTypeDefs()
+	relPkg, strictRelPkg := pkgs.RelativePackageName(pkg, rootPkg)
+	pkgT := tds.TypeOf(relPkg, strictRelPkg)
+	if len(pkgT.Def.MayNotExpose) == 0 && len(pkgT.Def.MayNotExposePackages) == 0 {
+		return nil
+	}
+	unqPkg := pkgs.UniquePackageName(relPkg, strictRelPkg)
+
+	for _, astf := range pkg.Syntax {
+		for _, decl := range astf.Decls {
+			for _, id := range exportedNames(decl) {
+				obj := pkg.TypesInfo.Defs[id]
+				if obj == nil {
+					continue
+				}
+				leaks := map[*types.Package]string{}
+				var order []*types.Package
+				w := apiWalker{own: pkg.Types, seen: map[types.Type]bool{}, found: func(p *types.Package, t string) {
+					if _, ok := leaks[p]; !ok {
+						leaks[p] = t
+						order = append(order, p)
+					}
+				}}
+				if _, ok := obj.(*types.TypeName); ok {
+					w.walk(obj.Type().Underlying())
+				} else {
+					w.walk(obj.Type())
+				}
+				for _, p := range order {
+					if err := checkExposedPackage(p, leaks[p], pkgT, unqPkg, apiName(obj),
+						pkg.Fset.Position(id.Pos()), rootPkg, tds); err != nil {
+						errs = append(errs, &data.Violation{
+							Check: "api", Pkg: unqPkg, Target: apiName(obj) + " -> " + leaks[p], Msg: err.Error(),
+						})
+					}
+				}
+			}
+		}
+	}
+	return errs
+}
+
+// checkExposedPackage checks that the type t of the package p may be exposed
+// in the API of the package.
+func checkExposedPackage(
+	p *types.Package, t string,
+	pkgT config.TypeInfo,
+	unqPkg, name string, pos token.Position,
+	rootPkg string, tds config.TypeDefs,
+) error {
+	relExp, strictRelExp := pkgs.RelativeImportName(&pkgs.Package{PkgPath: p.Path(), Name: p.Name()}, rootPkg)
+	unqExp := pkgs.UniquePackageName(relExp, strictRelExp)
+
+	if expT := tds.TypeOf(relExp, strictRelExp); config.ContainsType(pkgT.Def.MayNotExpose, expT.Type) {
+		return fmt.Errorf("%s '%s' isn't allowed to expose type '%s' of %s '%s' in '%s' at %s%s",
+			config.TypeLabel(pkgT.Type), unqPkg, t, config.TypeLabel(expT.Type), unqExp, name, pos,
+			data.Because("types", "`"+pkgT.Def.Name+"` mayNotExpose "+config.TypeNames(pkgT.Def.MayNotExpose),
+				pkgT.Def.Location, ""))
+	}
+	if e, full := pkgT.Def.MayNotExposePackages.MatchPackage(relExp, strictRelExp); full {
+		return fmt.Errorf("%s '%s' isn't allowed to expose type '%s' of package '%s' in '%s' at %s%s",
+			config.TypeLabel(pkgT.Type), unqPkg, t, unqExp, name, pos,
+			data.Because("types", "`"+pkgT.Def.Name+"` mayNotExposePackages `"+e.Pattern+"`", e.Location, e.Reason))
+	}
+	return nil
+}
+
+// exportedNames returns the identifiers of the exported API that are declared
+// by the given declaration.
+// Methods count only if their receiver type is exported.
+func exportedNames(decl ast.Decl) []*ast.Ident {
+	switch decl := decl.(type) {
+	case *ast.FuncDecl:
+		if !decl.Name.IsExported() {
+			return nil
+		}
+		if decl.Recv != nil && len(decl.Recv.List) > 0 && !isExportedReceiver(decl.Recv.List[0].Type) {
+			return nil
+		}
+		return []*ast.Ident{decl.Name}
+	case *ast.GenDecl:
+		var ids []*ast.Ident
+		for _, spec := range decl.Specs {
+			switch spec := spec.(type) {
+			case *ast.TypeSpec:
+				if spec.Name.IsExported() {
+					ids = append(ids, spec.Name)
+				}
+			case *ast.ValueSpec:
+				for _, id := range spec.Names {
+					if id.IsExported() {
+						ids = append(ids, id)
+					}
+				}
+			}
+		}
+		return ids
+	}
+	return nil
+}
+
+func isExportedReceiver(expr ast.Expr) bool {
+	for {
+		switch e := expr.(type) {
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.ParenExpr:
+			expr = e.X
+		case *ast.IndexExpr: // generic receiver
+			expr = e.X
+		case *ast.Ident:
+			return e.IsExported()
+		default:
+			return false
+		}
+	}
+}
+
+// apiName returns the name of the object as used in error messages:
+// `Func`, `Type`, `Var` or `Recv.Method`.
+func apiName(obj types.Object) string {
+	if fn, ok := obj.(*types.Func); ok {
+		if sig, ok := fn.Type().(*types.Signature); ok && sig.Recv() != nil {
+			t := sig.Recv().Type()
+			if p, ok := t.(*types.Pointer); ok {
+				t = p.Elem()
+			}
+			if n, ok := t.(*types.Named); ok {
+				return n.Obj().Name() + "." + fn.Name()
+			}
+		}
+	}
+	return obj.Name()
+}
+
+// apiWalker finds the named types of other packages that are visible in the
+// exported API of its own package.
+type apiWalker struct {
+	own   *types.Package
+	seen  map[types.Type]bool
+	found func(p *types.Package, t string)
+}
+
+func (w apiWalker) walk(t types.Type) {
+	if t == nil || w.seen[t] {
+		return
+	}
+	w.seen[t] = true
+
+	switch t := t.(type) {
+	case *types.Named:
+		obj := t.Obj()
+		switch {
+		case obj.Pkg() == nil: // built-in type like error
+		case obj.Pkg() != w.own:
+			w.found(obj.Pkg(), obj.Pkg().Path()+"."+obj.Name())
+		case !obj.Exported(): // exported types are checked on their own
+			w.walk(t.Underlying())
+		}
+	case *types.Pointer:
+		w.walk(t.Elem())
+	case *types.Slice:
+		w.walk(t.Elem())
+	case *types.Array:
+		w.walk(t.Elem())
+	case *types.Map:
+		w.walk(t.Key())
+		w.walk(t.Elem())
+	case *types.Chan:
+		w.walk(t.Elem())
+	case *types.Signature:
+		w.walk(t.Params())
+		w.walk(t.Results())
+	case *types.Tuple:
+		for i := 0; i < t.Len(); i++ {
+			w.walk(t.At(i).Type())
+		}
+	case *types.Struct:
+		for i := 0; i < t.NumFields(); i++ {
+			if f := t.Field(i); f.Exported() {
+				w.walk(f.Type())
+			}
+		}
+	case *types.Interface:
+		for i := 0; i < t.NumExplicitMethods(); i++ {
+			if m := t.ExplicitMethod(i); m.Exported() {
+				w.walk(m.Type())
+			}
+		}
+		for i := 0; i < t.NumEmbeddeds(); i++ {
+			w.walk(t.EmbeddedType(i))
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package api_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/flowdev/spaghetti-cutter/api"
+	"github.com/flowdev/spaghetti-cutter/config"
+	"github.com/flowdev/spaghetti-cutter/parse"
+	"github.com/flowdev/spaghetti-cutter/testutil"
+	"github.com/flowdev/spaghetti-cutter/x/pkgs"
+)
+
+func TestCheck(t *testing.T) {
+	specs := []struct {
+		name            string
+		givenConfig     string
+		expectedErrors  int
+		expectedMessage string // one of the errors, if given
+	}{
+		{
+			name:           "no-config",
+			givenConfig:    `{"db": ["pkg/db"]}`,
+			expectedErrors: 0,
+		}, {
+			name: "db-types",
+			givenConfig: `{
+				"db": ["pkg/db"],
+				"types": [{"name": "standard", "mayNotExpose": ["db"]}]
+			}`,
+			expectedErrors: 3,
+			expectedMessage: "domain package 'pkg/domain' isn't allowed to expose type " +
+				"'github.com/flowdev/spaghetti-cutter/api/testdata/api-proj/pkg/db.Conn' of DB package 'pkg/db' " +
+				"in 'Repo.Conn' at " + filepath.Join(testutil.MustAbs(filepath.Join("testdata", "api-proj")), "pkg", "domain", "domain.go") +
+				":21:16 (because of types `standard` mayNotExpose `db` in api-db-types:3)",
+		}, {
+			name: "db-types-and-packages",
+			givenConfig: `{
+				"db": ["pkg/db"],
+				"types": [{"name": "standard", "mayNotExpose": ["db"], "mayNotExposePackages": ["std:database/**"]}]
+			}`,
+			expectedErrors: 4,
+			expectedMessage: "domain package 'pkg/domain' isn't allowed to expose type 'database/sql.DB' of package " +
+				"'database/sql' in 'Repo' at " + filepath.Join(testutil.MustAbs(filepath.Join("testdata", "api-proj")), "pkg", "domain", "domain.go") +
+				":10:6 (because of types `standard` mayNotExposePackages `std:database/**` in api-db-types-and-packages:3)",
+		}, {
+			name: "db-may-expose-everything",
+			givenConfig: `{
+				"db": ["pkg/db"],
+				"types": [{"name": "db", "mayNotExposePackages": ["std:database/sql"]}]
+			}`,
+			expectedErrors: 1,
+		},
+	}
+
+	root := testutil.MustAbs(filepath.Join("testdata", "api-proj"))
+
+	for _, spec := range specs {
+		t.Run(spec.name, func(t *testing.T) {
+			cfg, err := config.Parse([]byte(spec.givenConfig), "api-"+spec.name)
+			if err != nil {
+				t.Fatalf("got unexpected error: %v", err)
+			}
+
+			packs, rootPkg, err := parse.DirTree(root, cfg.Exclude)
+			if err != nil {
+				t.Fatalf("Fatal parse error: %v", err)
+			}
+			if err = parse.TypeCheck(root, packs); err != nil {
+				t.Fatalf("Fatal type check error: %v", err)
+			}
+
+			var errs []string
+			for _, pkgInfo := range pkgs.UniquePackages(packs) {
+				errs = addErrors(errs, api.Check(pkgInfo.Pkg, rootPkg, cfg))
+			}
+			if len(errs) != spec.expectedErrors {
+				t.Errorf("Expected %d errors but got %d: %q", spec.expectedErrors, len(errs), errs)
+			}
+			if spec.expectedMessage != "" && !testutil.ContainsString(errs, spec.expectedMessage) {
+				t.Errorf("Expected error %q but got: %q", spec.expectedMessage, errs)
+			}
+		})
+	}
+}
+
+func addErrors(allErrs []string, newErrs []error) []string {
+	for _, err := range newErrs {
+		allErrs = append(allErrs, err.Error())
+	}
+	return allErrs
+}
@@ -0,0 +1,3 @@
+module github.com/flowdev/spaghetti-cutter/api/testdata/api-proj
+
+go 1.14
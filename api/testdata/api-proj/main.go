@@ -0,0 +1,11 @@
+package main
+
+import (
+	"github.com/flowdev/spaghetti-cutter/api/testdata/api-proj/pkg/db"
+	"github.com/flowdev/spaghetti-cutter/api/testdata/api-proj/pkg/domain"
+)
+
+func main() {
+	r := domain.NewRepo(db.Open())
+	_ = domain.Load(r.Conn())
+}
@@ -0,0 +1,18 @@
+package db
+
+import "database/sql"
+
+// Conn is a connection to the database.
+type Conn struct {
+	raw *sql.DB
+}
+
+// Open opens a connection to the database.
+func Open() *Conn {
+	return &Conn{}
+}
+
+// Raw returns the underlying connection pool.
+func (c *Conn) Raw() *sql.DB {
+	return c.raw
+}
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"database/sql"
+
+	"github.com/flowdev/spaghetti-cutter/api/testdata/api-proj/pkg/db"
+)
+
+// Repo stores the domain objects.
+type Repo struct {
+	DB   *sql.DB
+	conn *db.Conn
+}
+
+// NewRepo creates a new repository.
+func NewRepo(conn *db.Conn) *Repo {
+	return &Repo{DB: conn.Raw(), conn: conn}
+}
+
+// Conn returns the connection of the repository.
+func (r *Repo) Conn() *db.Conn {
+	return r.conn
+}
+
+// Load loads the domain objects.
+func Load(c *db.Conn) error {
+	return helper(c)
+}
+
+func helper(c *db.Conn) error {
+	return nil
+}
+
+type options struct {
+	conn *db.Conn
+}
+
+// Option configures the domain.
+type Option func(*options)
@@ -0,0 +1,151 @@
+// Package baseline records known violations in a file, so only new
+// violations are reported.
+package baseline
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+
+	"github.com/flowdev/spaghetti-cutter/data"
+)
+
+// File is the name of the baseline file.
+// It is stored in the root directory of the project next to the
+// configuration file.
+const File = ".spaghetti-cutter-baseline.json"
+
+// Entry is a single known violation.
+// Count is the number of its occurrences (0 means 1) and Size is the maximum
+// measured size of a size or fan-in/fan-out violation.
+type Entry struct {
+	Fingerprint string `json:"fingerprint"`
+	Message     string `json:"message"`
+	Count       int    `json:"count,omitempty"`
+	Size        uint   `json:"size,omitempty"`
+}
+
+// Baseline contains all known violations.
+type Baseline struct {
+	Violations []Entry `json:"violations"`
+}
+
+// New creates a baseline containing all the given errors.
+// Errors with the same fingerprint are counted.
+func New(errs []error) Baseline {
+	seen := make(map[string]int, len(errs))
+	entries := make([]Entry, 0, len(errs))
+	for _, err := range errs {
+		fp := Fingerprint(err)
+		if i, ok := seen[fp]; ok {
+			entries[i].Count = entries[i].count() + 1
+			continue
+		}
+		seen[fp] = len(entries)
+		entries = append(entries, Entry{Fingerprint: fp, Message: err.Error(), Size: size(err)})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Fingerprint < entries[j].Fingerprint
+	})
+	return Baseline{Violations: entries}
+}
+
+// Fingerprint returns a stable identification of the given error.
+// Violations have got their own fingerprint and all other errors are
+// identified by their message.
+func Fingerprint(err error) string {
+	var v *data.Violation
+	if errors.As(err, &v) {
+		return v.Fingerprint()
+	}
+	return err.Error()
+}
+
+// size returns the measured size of a violation (e.g. of a size or fan-in
+// violation) or 0.
+func size(err error) uint {
+	var v *data.Violation
+	if errors.As(err, &v) {
+		return v.Size
+	}
+	return 0
+}
+
+func (e Entry) count() int {
+	if e.Count <= 0 {
+		return 1
+	}
+	return e.Count
+}
+
+// Read reads the baseline from the given file.
+// An empty baseline is returned if the file doesn't exist.
+func Read(file string) (Baseline, error) {
+	content, err := ioutil.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return Baseline{}, nil
+	}
+	if err != nil {
+		return Baseline{}, fmt.Errorf("unable to read baseline file %q: %w", file, err)
+	}
+
+	bl := Baseline{}
+	if err = json.Unmarshal(content, &bl); err != nil {
+		return Baseline{}, fmt.Errorf("unable to unmarshal baseline file %q: %w", file, err)
+	}
+	return bl, nil
+}
+
+// Write writes the baseline to the given file.
+func (bl Baseline) Write(file string) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false) // keep fingerprints readable
+	enc.SetIndent("", "\t")
+	if err := enc.Encode(bl); err != nil {
+		return fmt.Errorf("unable to marshal baseline: %w", err)
+	}
+	if err := ioutil.WriteFile(file, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("unable to write baseline file %q: %w", file, err)
+	}
+	return nil
+}
+
+// Subtract removes all errors that are known in the baseline.
+// Every entry covers as many errors as it has got occurrences and a violation
+// with a measured size only as long as the size doesn't grow.
+// The remaining (new) errors are returned together with the baseline entries
+// that don't occur (as often) anymore. Their Count is the number of missing
+// occurrences.
+func (bl Baseline) Subtract(errs []error) (newErrs []error, fixed []Entry) {
+	left := make(map[string]int, len(bl.Violations))
+	sizes := make(map[string]uint, len(bl.Violations))
+	for _, e := range bl.Violations {
+		left[e.Fingerprint] += e.count()
+		sizes[e.Fingerprint] = e.Size
+	}
+
+	for _, err := range errs {
+		fp := Fingerprint(err)
+		if left[fp] > 0 {
+			left[fp]--
+			if size(err) <= sizes[fp] {
+				continue
+			}
+		}
+		newErrs = append(newErrs, err)
+	}
+
+	for _, e := range bl.Violations {
+		if n := left[e.Fingerprint]; n > 0 {
+			e.Count = n
+			fixed = append(fixed, e)
+			left[e.Fingerprint] = 0
+		}
+	}
+	return newErrs, fixed
+}
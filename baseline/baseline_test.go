@@ -0,0 +1,122 @@
+package baseline_test
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/flowdev/spaghetti-cutter/baseline"
+	"github.com/flowdev/spaghetti-cutter/data"
+)
+
+func TestSubtract(t *testing.T) {
+	specs := []struct {
+		name             string
+		givenBaseline    []error
+		givenErrs        []error
+		expectedNewErrs  []string
+		expectedFixedFPs []string
+	}{
+		{
+			name:             "empty-baseline",
+			givenBaseline:    nil,
+			givenErrs:        []error{errors.New("a"), violation("deps", "a", "b", "a imports b")},
+			expectedNewErrs:  []string{"a", "a imports b"},
+			expectedFixedFPs: nil,
+		}, {
+			name:             "all-known",
+			givenBaseline:    []error{errors.New("a"), sizeViolation("a", 13)},
+			givenErrs:        []error{sizeViolation("a", 12), errors.New("a")},
+			expectedNewErrs:  nil,
+			expectedFixedFPs: nil,
+		}, {
+			name:             "size-grown",
+			givenBaseline:    []error{sizeViolation("a", 12)},
+			givenErrs:        []error{sizeViolation("a", 13)},
+			expectedNewErrs:  []string{"a is too big: 13"},
+			expectedFixedFPs: nil,
+		}, {
+			name: "more-occurrences",
+			givenBaseline: []error{
+				violation("calls", "a", "os.Exit in main", "a calls os.Exit at 1"),
+			},
+			givenErrs: []error{
+				violation("calls", "a", "os.Exit in main", "a calls os.Exit at 1"),
+				violation("calls", "a", "os.Exit in main", "a calls os.Exit at 2"),
+			},
+			expectedNewErrs:  []string{"a calls os.Exit at 2"},
+			expectedFixedFPs: nil,
+		}, {
+			name: "fewer-occurrences",
+			givenBaseline: []error{
+				violation("calls", "a", "os.Exit in main", "a calls os.Exit at 1"),
+				violation("calls", "a", "os.Exit in main", "a calls os.Exit at 2"),
+			},
+			givenErrs: []error{
+				violation("calls", "a", "os.Exit in main", "a calls os.Exit at 1"),
+			},
+			expectedNewErrs:  nil,
+			expectedFixedFPs: []string{"calls: a -> os.Exit in main"},
+		}, {
+			name:             "new-and-fixed",
+			givenBaseline:    []error{violation("deps", "a", "b", "a imports b"), violation("deps", "a", "c", "a imports c")},
+			givenErrs:        []error{violation("deps", "a", "c", "a imports c"), violation("deps", "a", "d", "a imports d")},
+			expectedNewErrs:  []string{"a imports d"},
+			expectedFixedFPs: []string{"deps: a -> b"},
+		},
+	}
+
+	for _, spec := range specs {
+		t.Run(spec.name, func(t *testing.T) {
+			bl := baseline.New(spec.givenBaseline)
+			file := filepath.Join(t.TempDir(), baseline.File)
+			if err := bl.Write(file); err != nil {
+				t.Fatalf("received UNexpected error: %v", err)
+			}
+			bl, err := baseline.Read(file)
+			if err != nil {
+				t.Fatalf("received UNexpected error: %v", err)
+			}
+
+			actualNewErrs, actualFixed := bl.Subtract(spec.givenErrs)
+			if actual := errorStrings(actualNewErrs); !reflect.DeepEqual(actual, spec.expectedNewErrs) {
+				t.Errorf("expected new errors %q, actual %q", spec.expectedNewErrs, actual)
+			}
+			var actualFixedFPs []string
+			for _, e := range actualFixed {
+				actualFixedFPs = append(actualFixedFPs, e.Fingerprint)
+			}
+			if !reflect.DeepEqual(actualFixedFPs, spec.expectedFixedFPs) {
+				t.Errorf("expected fixed entries %q, actual %q", spec.expectedFixedFPs, actualFixedFPs)
+			}
+		})
+	}
+}
+
+func TestReadMissingFile(t *testing.T) {
+	bl, err := baseline.Read(filepath.Join(t.TempDir(), baseline.File))
+	if err != nil {
+		t.Fatalf("received UNexpected error: %v", err)
+	}
+	if len(bl.Violations) != 0 {
+		t.Errorf("expected empty baseline, actual %v", bl)
+	}
+}
+
+func violation(check, pkg, target, msg string) error {
+	return &data.Violation{Check: check, Pkg: pkg, Target: target, Msg: msg}
+}
+
+func sizeViolation(pkg string, size uint) error {
+	return &data.Violation{Check: "size", Pkg: pkg, Msg: fmt.Sprintf("%s is too big: %d", pkg, size), Size: size}
+}
+
+func errorStrings(errs []error) []string {
+	var strs []string
+	for _, err := range errs {
+		strs = append(strs, err.Error())
+	}
+	return strs
+}
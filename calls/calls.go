@@ -0,0 +1,109 @@
+// Package calls checks that certain functions and methods are only called in
+// the packages that are allowed to call them.
+package calls
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+
+	"github.com/flowdev/spaghetti-cutter/config"
+	"github.com/flowdev/spaghetti-cutter/data"
+	"github.com/flowdev/spaghetti-cutter/x/pkgs"
+)
+
+// Check reports every call of a function or method that the package isn't
+// allowed to call according to the `allowCallOnlyIn` configuration.
+// Functions are named by their package path and name (e.g. `os.Exit`) and
+// methods additionally by the name of the receiver type
+// (e.g. `net/http.Client.Do`).
+// The type information of the package has to be computed already
+// (see: parse.TypeCheck). Test packages aren't checked.
+func Check(pkg *pkgs.Package, rootPkg string, cfg config.Config) []error {
+	if cfg.AllowCallOnlyIn == nil || pkg.TypesInfo == nil || pkgs.IsTestPackage(pkg) {
+		return nil
+	}
+	relPkg, strictRelPkg := pkgs.RelativePackageName(pkg, rootPkg)
+	unqPkg := pkgs.UniquePackageName(relPkg, strictRelPkg)
+
+	var errs []error
+	for _, astf := range pkg.Syntax {
+		for _, decl := range astf.Decls {
+			errs = append(errs, checkDecl(decl, pkg, unqPkg, relPkg, strictRelPkg, cfg)...)
+		}
+	}
+	return errs
+}
+
+// checkDecl checks the calls in a single declaration.
+// The violations are identified by the called function and the declared
+// function around the call.
+func checkDecl(decl ast.Decl, pkg *pkgs.Package, unqPkg, relPkg, strictRelPkg string, cfg config.Config) []error {
+	in := ""
+	if fun, ok := decl.(*ast.FuncDecl); ok {
+		in = " in " + pkgs.FuncName(fun)
+	}
+	var errs []error
+	ast.Inspect(decl, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		fn := calledFunc(call, pkg.TypesInfo)
+		if fn == nil {
+			return true
+		}
+		name := FuncName(fn)
+		group, allowed := cfg.AllowCallOnlyIn.MatchKeyValue(name, "", relPkg, strictRelPkg)
+		if group == nil || allowed != nil {
+			return true
+		}
+		errs = append(errs, &data.Violation{Check: "calls", Pkg: unqPkg, Target: name + in, Msg: fmt.Sprintf(
+			"package '%s' isn't allowed to call '%s' at %s%s",
+			unqPkg, name, pkg.Fset.Position(call.Pos()),
+			data.Because("allowCallOnlyIn", "`"+group.Left.Pattern+"`", group.Left.Location, group.Left.Reason))})
+		return true
+	})
+	return errs
+}
+
+// calledFunc returns the function or method that is called (or nil for calls
+// of function values, built-in functions and conversions).
+func calledFunc(call *ast.CallExpr, info *types.Info) *types.Func {
+	fun := call.Fun
+	for p, ok := fun.(*ast.ParenExpr); ok; p, ok = fun.(*ast.ParenExpr) {
+		fun = p.X
+	}
+	var id *ast.Ident
+	switch fun := fun.(type) {
+	case *ast.Ident:
+		id = fun
+	case *ast.SelectorExpr:
+		id = fun.Sel
+	case *ast.IndexExpr: // generic function with explicit type argument
+		return calledFunc(&ast.CallExpr{Fun: fun.X}, info)
+	default:
+		return nil
+	}
+	fn, _ := info.Uses[id].(*types.Func)
+	return fn
+}
+
+// FuncName returns the fully qualified name of the function or method:
+// `<package path>.<func>` or `<package path>.<receiver type>.<method>`.
+func FuncName(fn *types.Func) string {
+	if fn.Pkg() == nil { // e.g. the Error method of the built-in error type
+		return fn.Name()
+	}
+	name := fn.Pkg().Path() + "."
+	if sig, ok := fn.Type().(*types.Signature); ok && sig.Recv() != nil {
+		t := sig.Recv().Type()
+		if p, ok := t.(*types.Pointer); ok {
+			t = p.Elem()
+		}
+		if n, ok := t.(*types.Named); ok {
+			name += n.Obj().Name() + "."
+		}
+	}
+	return name + fn.Name()
+}
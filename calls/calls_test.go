@@ -0,0 +1,90 @@
+package calls_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/flowdev/spaghetti-cutter/calls"
+	"github.com/flowdev/spaghetti-cutter/config"
+	"github.com/flowdev/spaghetti-cutter/parse"
+	"github.com/flowdev/spaghetti-cutter/testutil"
+	"github.com/flowdev/spaghetti-cutter/x/pkgs"
+)
+
+func TestCheck(t *testing.T) {
+	specs := []struct {
+		name            string
+		givenConfig     string
+		expectedErrors  int
+		expectedMessage string // part of one of the errors, if given
+	}{
+		{
+			name:           "no-config",
+			givenConfig:    `{}`,
+			expectedErrors: 0,
+		}, {
+			name: "functions",
+			givenConfig: `{
+					"allowCallOnlyIn": {
+						"os.Exit": ["main"],
+						"os.Getenv": {"reason": "configuration belongs into the config package", "patterns": ["pkg/config"]},
+						"time.Now": ["main"],
+						"log.Fatal*": ["main"]
+					}
+				}`,
+			expectedErrors: 3,
+			expectedMessage: "domain.go:14:10 (because of allowCallOnlyIn `os.Getenv` in functions:4: " +
+				"configuration belongs into the config package)",
+		}, {
+			name:            "methods",
+			givenConfig:     `{"allowCallOnlyIn": {"strings.Builder.*": ["pkg/config"]}}`,
+			expectedErrors:  2,
+			expectedMessage: "package 'pkg/domain' isn't allowed to call 'strings.Builder.WriteString' at ",
+		}, {
+			name:           "allowed-everywhere",
+			givenConfig:    `{"allowCallOnlyIn": {"os.*": ["**"]}}`,
+			expectedErrors: 0,
+		},
+	}
+
+	root := testutil.MustAbs(filepath.Join("testdata", "calls-proj"))
+	packs, rootPkg, err := parse.DirTree(root, nil)
+	if err != nil {
+		t.Fatalf("Fatal parse error: %v", err)
+	}
+	if err = parse.TypeCheck(root, packs); err != nil {
+		t.Fatalf("Fatal type check error: %v", err)
+	}
+
+	for _, spec := range specs {
+		t.Run(spec.name, func(t *testing.T) {
+			cfg, err := config.Parse([]byte(spec.givenConfig), spec.name)
+			if err != nil {
+				t.Fatalf("got unexpected error: %v", err)
+			}
+
+			var errs []string
+			for _, pkgInfo := range pkgs.UniquePackages(packs) {
+				for _, err := range calls.Check(pkgInfo.Pkg, rootPkg, cfg) {
+					errs = append(errs, err.Error())
+				}
+			}
+			if len(errs) != spec.expectedErrors {
+				t.Errorf("Expected %d errors but got %d: %q", spec.expectedErrors, len(errs), errs)
+			}
+			if spec.expectedMessage != "" && !containsPart(errs, spec.expectedMessage) {
+				t.Errorf("Expected error containing %q but got: %q", spec.expectedMessage, errs)
+			}
+		})
+	}
+}
+
+func containsPart(ss []string, s string) bool {
+	for _, t := range ss {
+		if strings.Contains(t, s) {
+			return true
+		}
+	}
+	return false
+}
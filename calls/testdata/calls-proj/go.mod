@@ -0,0 +1,3 @@
+module github.com/flowdev/spaghetti-cutter/calls/testdata/calls-proj
+
+go 1.14
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"log"
+	"os"
+	"time"
+
+	"github.com/flowdev/spaghetti-cutter/calls/testdata/calls-proj/pkg/config"
+	"github.com/flowdev/spaghetti-cutter/calls/testdata/calls-proj/pkg/domain"
+)
+
+func main() {
+	start := time.Now()
+	if err := domain.Do(config.Name()); err != nil {
+		log.Printf("ERROR - %v", err)
+		os.Exit(1)
+	}
+	log.Printf("INFO - done in %v", time.Since(start))
+}
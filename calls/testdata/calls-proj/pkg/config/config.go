@@ -0,0 +1,8 @@
+package config
+
+import "os"
+
+// Name returns the configured name.
+func Name() string {
+	return os.Getenv("NAME")
+}
@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"errors"
+	"log"
+	"os"
+	"strings"
+	"time"
+)
+
+// Do does the real work.
+func Do(name string) error {
+	if name == "" {
+		name = os.Getenv("USER")
+	}
+	if name == "" {
+		log.Fatalf("FATAL - no name")
+	}
+	b := strings.Builder{}
+	b.WriteString(name)
+	if (time.Now()).Weekday() == time.Sunday {
+		return errors.New("no work on sundays: " + b.String())
+	}
+	return nil
+}
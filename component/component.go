@@ -0,0 +1,181 @@
+// Package component checks the dependencies between components (named groups
+// of packages) for cycles and dependencies that aren't allowed.
+package component
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/flowdev/spaghetti-cutter/config"
+	"github.com/flowdev/spaghetti-cutter/data"
+	"github.com/flowdev/spaghetti-cutter/x/pkgs"
+)
+
+// edge is a dependency between two components (indexes of the components in
+// the configuration).
+type edge struct {
+	from, to int
+}
+
+// Check builds the component graph from the dependencies of the given
+// packages and reports cycles and dependencies that aren't allowed together
+// with the package imports responsible for them.
+// The dependencies of the packages have to be linked already
+// (see: pkgs.LinkPackages).
+// Only violations caused by an import of a package in scope (unique relative
+// package names) are reported. A nil scope contains all packages.
+func Check(pkgInfos map[string]*pkgs.PackageInfo, rootPkg string, scope map[string]bool, cfg config.Config) []error {
+	comps := cfg.Components
+	if len(comps) == 0 {
+		return nil
+	}
+	imps, scoped := componentImports(pkgInfos, rootPkg, scope, comps)
+	edges := make([]edge, 0, len(imps))
+	for e := range imps {
+		edges = append(edges, e)
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].from != edges[j].from {
+			return edges[i].from < edges[j].from
+		}
+		return edges[i].to < edges[j].to
+	})
+
+	var errs []error
+	for _, e := range edges {
+		c := comps[e.from]
+		if !scoped[e] || c.MayImport == nil || containsString(c.MayImport, comps[e.to].Name) {
+			continue
+		}
+		errs = append(errs, &data.Violation{
+			Check: "components", Pkg: c.Name, Target: comps[e.to].Name,
+			Msg: fmt.Sprintf("component '%s' isn't allowed to use component '%s' (imports: %s)%s",
+				c.Name, comps[e.to].Name, strings.Join(imps[e], ", "), data.Because("components", "`"+c.Name+"`", c.Location, "")),
+		})
+	}
+
+	for _, cycle := range cycles(len(comps), edges) {
+		names := make([]string, len(cycle))
+		inCycle := make(map[int]bool, len(cycle))
+		for i, c := range cycle {
+			names[i] = comps[c].Name
+			inCycle[c] = true
+		}
+		var deps []string
+		inScope := false
+		for _, e := range edges {
+			if inCycle[e.from] && inCycle[e.to] {
+				deps = append(deps, fmt.Sprintf("%s -> %s (imports: %s)",
+					comps[e.from].Name, comps[e.to].Name, strings.Join(imps[e], ", ")))
+				inScope = inScope || scoped[e]
+			}
+		}
+		if !inScope {
+			continue
+		}
+		errs = append(errs, &data.Violation{
+			Check: "component-cycle", Pkg: names[0], Target: strings.Join(names[1:], ", "),
+			Msg: fmt.Sprintf("the components '%s' depend on each other: %s",
+				strings.Join(names, "', '"), strings.Join(deps, "; ")),
+		})
+	}
+	return errs
+}
+
+// componentImports returns the sorted package imports (`pkg -> imp`) per
+// dependency between different components and which dependencies are caused
+// by packages in scope (see: Check).
+// Test packages are ignored.
+func componentImports(pkgInfos map[string]*pkgs.PackageInfo, rootPkg string, scope map[string]bool,
+	comps config.Components,
+) (imps map[edge][]string, scoped map[edge]bool) {
+	imps = make(map[edge][]string)
+	scoped = make(map[edge]bool)
+	for _, pkgInfo := range pkgInfos {
+		relPkg, strictRelPkg := pkgs.RelativePackageName(pkgInfo.Pkg, rootPkg)
+		unqPkg := pkgs.UniquePackageName(relPkg, strictRelPkg)
+		from := componentOf(relPkg, strictRelPkg, comps)
+		if from < 0 || strings.HasSuffix(unqPkg, "_test") {
+			continue
+		}
+		for _, dep := range pkgInfo.Deps {
+			relDep, strictRelDep := pkgs.RelativePackageName(dep.Pkg, rootPkg)
+			to := componentOf(relDep, strictRelDep, comps)
+			if to < 0 || to == from {
+				continue
+			}
+			e := edge{from: from, to: to}
+			imps[e] = append(imps[e], unqPkg+" -> "+pkgs.UniquePackageName(relDep, strictRelDep))
+			scoped[e] = scoped[e] || scope == nil || scope[unqPkg]
+		}
+	}
+	for _, l := range imps {
+		sort.Strings(l)
+	}
+	return imps, scoped
+}
+
+// componentOf returns the index of the first component that contains the
+// package or -1 if the package isn't part of any component.
+func componentOf(relPkg, strictRelPkg string, comps config.Components) int {
+	for i, c := range comps {
+		for _, pkg := range []string{strictRelPkg, relPkg} {
+			if pkg == "" {
+				continue
+			}
+			if _, full := c.Patterns.MatchString(pkg, nil); full {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// cycles returns the groups of components that depend on each other
+// (the strongly connected components of the graph with more than one node).
+// The graph is small, so the transitive closure is good enough.
+func cycles(n int, edges []edge) [][]int {
+	reach := make([][]bool, n)
+	for i := range reach {
+		reach[i] = make([]bool, n)
+	}
+	for _, e := range edges {
+		reach[e.from][e.to] = true
+	}
+	for k := 0; k < n; k++ {
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				reach[i][j] = reach[i][j] || reach[i][k] && reach[k][j]
+			}
+		}
+	}
+
+	var result [][]int
+	done := make([]bool, n)
+	for i := 0; i < n; i++ {
+		if done[i] {
+			continue
+		}
+		cycle := []int{i}
+		for j := i + 1; j < n; j++ {
+			if reach[i][j] && reach[j][i] {
+				cycle = append(cycle, j)
+				done[j] = true
+			}
+		}
+		if len(cycle) > 1 {
+			result = append(result, cycle)
+		}
+	}
+	return result
+}
+
+func containsString(ss []string, s string) bool {
+	for _, t := range ss {
+		if t == s {
+			return true
+		}
+	}
+	return false
+}
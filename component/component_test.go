@@ -0,0 +1,121 @@
+package component_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/flowdev/spaghetti-cutter/component"
+	"github.com/flowdev/spaghetti-cutter/config"
+	"github.com/flowdev/spaghetti-cutter/parse"
+	"github.com/flowdev/spaghetti-cutter/testutil"
+	"github.com/flowdev/spaghetti-cutter/x/pkgs"
+)
+
+func TestCheck(t *testing.T) {
+	specs := []struct {
+		name            string
+		givenConfig     string
+		givenScope      []string
+		expectedErrors  int
+		expectedMessage string // one of the errors, if given
+	}{
+		{
+			name:           "no-components",
+			givenConfig:    `{}`,
+			expectedErrors: 0,
+		}, {
+			name: "cycle",
+			givenConfig: `{
+						"components": [
+							{"name": "orders", "patterns": ["pkg/orders", "pkg/orders/**"]},
+							{"name": "billing", "patterns": ["pkg/billing", "pkg/billing/**"]},
+							{"name": "shipping", "patterns": ["pkg/shipping", "pkg/shipping/**"]}
+						]
+					}`,
+			expectedErrors: 1,
+			expectedMessage: "the components 'orders', 'billing' depend on each other: " +
+				"orders -> billing (imports: pkg/orders -> pkg/billing/api); " +
+				"billing -> orders (imports: pkg/billing -> pkg/orders/api)",
+		}, {
+			name: "allow-list",
+			givenConfig: `{
+						"components": [
+							{"name": "orders", "patterns": ["pkg/orders", "pkg/orders/**"], "mayImport": ["billing"]},
+							{"name": "billing", "patterns": ["pkg/billing", "pkg/billing/**"], "mayImport": []},
+							{"name": "shipping", "patterns": ["pkg/shipping", "pkg/shipping/**"], "mayImport": ["billing"]}
+						]
+					}`,
+			expectedErrors: 3,
+			expectedMessage: "component 'shipping' isn't allowed to use component 'orders' " +
+				"(imports: pkg/shipping -> pkg/orders/api) (because of components `shipping` in allow-list:5)",
+		}, {
+			name: "cycle-out-of-scope",
+			givenConfig: `{
+						"components": [
+							{"name": "orders", "patterns": ["pkg/orders", "pkg/orders/**"]},
+							{"name": "billing", "patterns": ["pkg/billing", "pkg/billing/**"]},
+							{"name": "shipping", "patterns": ["pkg/shipping", "pkg/shipping/**"]}
+						]
+					}`,
+			givenScope:     []string{"pkg/shipping"},
+			expectedErrors: 0,
+		}, {
+			name: "allow-list-in-scope",
+			givenConfig: `{
+						"components": [
+							{"name": "orders", "patterns": ["pkg/orders", "pkg/orders/**"], "mayImport": ["billing"]},
+							{"name": "billing", "patterns": ["pkg/billing", "pkg/billing/**"], "mayImport": []},
+							{"name": "shipping", "patterns": ["pkg/shipping", "pkg/shipping/**"], "mayImport": ["billing"]}
+						]
+					}`,
+			givenScope:     []string{"pkg/shipping"},
+			expectedErrors: 1,
+			expectedMessage: "component 'shipping' isn't allowed to use component 'orders' " +
+				"(imports: pkg/shipping -> pkg/orders/api) (because of components `shipping` in allow-list-in-scope:5)",
+		}, {
+			name: "allowed",
+			givenConfig: `{
+						"components": [
+							{"name": "orders", "patterns": ["pkg/orders", "pkg/orders/**"], "mayImport": []},
+							{"name": "shipping", "patterns": ["pkg/shipping", "pkg/shipping/**"], "mayImport": ["orders"]}
+						]
+					}`,
+			expectedErrors: 0,
+		},
+	}
+
+	packs, rootPkg, err := parse.DirTree(testutil.MustAbs(filepath.Join("testdata", "comp-proj")), nil)
+	if err != nil {
+		t.Fatalf("Fatal parse error: %v", err)
+	}
+	pkgInfos := pkgs.UniquePackages(packs)
+	pkgs.LinkPackages(pkgInfos, rootPkg)
+
+	for _, spec := range specs {
+		t.Run(spec.name, func(t *testing.T) {
+			cfg, err := config.Parse([]byte(spec.givenConfig), spec.name)
+			if err != nil {
+				t.Fatalf("got unexpected error: %v", err)
+			}
+
+			var scope map[string]bool
+			if spec.givenScope != nil {
+				scope = make(map[string]bool, len(spec.givenScope))
+				for _, s := range spec.givenScope {
+					scope[s] = true
+				}
+			}
+
+			var errs []string
+			for _, err := range component.Check(pkgInfos, rootPkg, scope, cfg) {
+				errs = append(errs, err.Error())
+			}
+			if len(errs) != spec.expectedErrors {
+				t.Errorf("Expected %d errors but got %d: %q", spec.expectedErrors, len(errs), errs)
+			}
+			if spec.expectedMessage != "" && !testutil.ContainsString(errs, spec.expectedMessage) {
+				t.Errorf("Expected error %q but got: %q", spec.expectedMessage, errs)
+			}
+		})
+	}
+}
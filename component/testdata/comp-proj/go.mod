@@ -0,0 +1,3 @@
+module github.com/flowdev/spaghetti-cutter/component/testdata/comp-proj
+
+go 1.14
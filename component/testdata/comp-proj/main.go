@@ -0,0 +1,13 @@
+package main
+
+import (
+	"github.com/flowdev/spaghetti-cutter/component/testdata/comp-proj/pkg/billing"
+	"github.com/flowdev/spaghetti-cutter/component/testdata/comp-proj/pkg/orders"
+	"github.com/flowdev/spaghetti-cutter/component/testdata/comp-proj/pkg/shipping"
+)
+
+func main() {
+	orders.Order()
+	billing.Bill()
+	shipping.Ship()
+}
@@ -0,0 +1,8 @@
+package billing
+
+import "github.com/flowdev/spaghetti-cutter/component/testdata/comp-proj/pkg/orders/api"
+
+// Bill is just here to be called.
+func Bill() {
+	_ = api.Status()
+}
@@ -0,0 +1,6 @@
+package api
+
+// Status is just here to be called.
+func Status() string {
+	return "ok"
+}
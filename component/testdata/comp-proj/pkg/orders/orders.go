@@ -0,0 +1,8 @@
+package orders
+
+import "github.com/flowdev/spaghetti-cutter/component/testdata/comp-proj/pkg/billing/api"
+
+// Order is just here to be called.
+func Order() {
+	api.Invoice()
+}
@@ -0,0 +1,8 @@
+package shipping
+
+import "github.com/flowdev/spaghetti-cutter/component/testdata/comp-proj/pkg/orders/api"
+
+// Ship is just here to be called.
+func Ship() {
+	_ = api.Status()
+}
@@ -0,0 +1,52 @@
+package code
+
+import (
+	"fmt"
+
+	"github.com/flowdev/spaghetti-cutter/config/convert"
+	"github.com/flowdev/spaghetti-cutter/data"
+)
+
+// Concurrency contains the packages that may use concurrency.
+type Concurrency struct {
+	AllowedIn data.PatternList // nil: concurrency isn't checked at all
+	Channels  bool             // select and send statements and channel types are checked, too
+	Location  string           // file and line of the concurrency in the configuration
+}
+
+// String implements Stringer and returns the packages that may use
+// concurrency and if channels are checked.
+func (c Concurrency) String() string {
+	return fmt.Sprintf("allowedIn: %s channels: %t", c.AllowedIn, c.Channels)
+}
+
+// ConcurrencyFromJSON converts the concurrency of the given key.
+func ConcurrencyFromJSON(i interface{}, keyConcurrency string) (Concurrency, error) {
+	c := Concurrency{}
+	if i == nil {
+		return c, nil
+	}
+
+	m, ok := i.(map[string]interface{})
+	if !ok {
+		return c, fmt.Errorf("expected map for key '%s', got type: %T", keyConcurrency, i)
+	}
+	for k := range m {
+		if k != "allowedIn" && k != "channels" {
+			return c, fmt.Errorf("unknown value '%s' for key '%s'", k, keyConcurrency)
+		}
+	}
+
+	var err error
+	c.AllowedIn, err = convert.PatternList(m["allowedIn"], keyConcurrency+" allowedIn", data.EnumDollarNone, 0)
+	if err != nil {
+		return Concurrency{}, err
+	}
+	if c.AllowedIn == nil {
+		c.AllowedIn = data.PatternList{} // concurrency is checked as soon as it is configured
+	}
+	if c.Channels, err = convert.Bool(m["channels"]); err != nil {
+		return Concurrency{}, fmt.Errorf("unable to convert 'channels' for key '%s' from JSON: %w", keyConcurrency, err)
+	}
+	return c, nil
+}
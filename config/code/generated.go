@@ -0,0 +1,81 @@
+// Package code contains the configuration of the checks of the code itself:
+// size weights, generated files, concurrency and purity.
+package code
+
+import (
+	"fmt"
+
+	"github.com/flowdev/spaghetti-cutter/config/convert"
+)
+
+// GeneratedMode tells a check how to handle generated files.
+type GeneratedMode int
+
+// Enum of modes for generated files: normal, ignore and separate
+const (
+	GeneratedNormal   GeneratedMode = iota // treat generated files like all others
+	GeneratedIgnore                        // ignore generated files completely
+	GeneratedSeparate                      // check and report generated files separately
+)
+
+var generatedModeNames = []string{"normal", "ignore", "separate"}
+
+// String implements Stringer and returns the name of the mode.
+func (m GeneratedMode) String() string {
+	return generatedModeNames[m]
+}
+
+// GeneratedFiles contains the modes for handling generated files per check.
+type GeneratedFiles struct {
+	Size GeneratedMode
+	Deps GeneratedMode
+}
+
+// GeneratedFilesFromJSON converts the modes for generated files of the given
+// key.
+func GeneratedFilesFromJSON(i interface{}, keyGeneratedFiles string) (GeneratedFiles, error) {
+	gf := GeneratedFiles{}
+
+	if i == nil {
+		return gf, nil
+	}
+
+	m, ok := i.(map[string]interface{})
+	if !ok {
+		return GeneratedFiles{}, fmt.Errorf("expected map for key '%s', got type: %T", keyGeneratedFiles, i)
+	}
+
+	modes := map[string]*GeneratedMode{
+		"size": &gf.Size,
+		"deps": &gf.Deps,
+	}
+	for k, v := range m {
+		mode, ok := modes[k]
+		if !ok {
+			return GeneratedFiles{}, fmt.Errorf("unknown check '%s' for key '%s'", k, keyGeneratedFiles)
+		}
+		s, err := convert.String(v)
+		if err != nil {
+			return GeneratedFiles{}, fmt.Errorf("unable to convert mode for check '%s' of key '%s' from JSON: %w",
+				k, keyGeneratedFiles, err)
+		}
+		if *mode, err = convertGeneratedModeFromString(s); err != nil {
+			return GeneratedFiles{}, fmt.Errorf("unable to use mode for check '%s' of key '%s': %w",
+				k, keyGeneratedFiles, err)
+		}
+	}
+
+	return gf, nil
+}
+
+func convertGeneratedModeFromString(s string) (GeneratedMode, error) {
+	if s == "" {
+		return GeneratedNormal, nil
+	}
+	for i, name := range generatedModeNames {
+		if s == name {
+			return GeneratedMode(i), nil
+		}
+	}
+	return GeneratedNormal, fmt.Errorf("expected one of %q, got: %q", generatedModeNames, s)
+}
@@ -0,0 +1,54 @@
+package code
+
+import (
+	"fmt"
+
+	"github.com/flowdev/spaghetti-cutter/config/convert"
+	"github.com/flowdev/spaghetti-cutter/data"
+)
+
+// Purity contains the packages that have to be free of side effects: they
+// may neither have package-level variables nor init functions.
+type Purity struct {
+	Tool        bool             // tool packages (including their sub-packages) have to be pure
+	Patterns    data.PatternList // other packages that have to be pure
+	AllowedVars data.PatternList // names of package-level variables that are allowed anyway
+	Location    string           // file and line of the purity in the configuration
+}
+
+// String implements Stringer and returns the packages that have to be pure
+// and the allowed variables.
+func (p Purity) String() string {
+	return fmt.Sprintf("tool: %t patterns: %s allowedVars: %s", p.Tool, p.Patterns, p.AllowedVars)
+}
+
+// PurityFromJSON converts the purity of the given key.
+func PurityFromJSON(i interface{}, keyPurity string) (Purity, error) {
+	p := Purity{}
+	if i == nil {
+		return p, nil
+	}
+
+	m, ok := i.(map[string]interface{})
+	if !ok {
+		return p, fmt.Errorf("expected map for key '%s', got type: %T", keyPurity, i)
+	}
+	for k := range m {
+		if k != "tool" && k != "patterns" && k != "allowedVars" {
+			return p, fmt.Errorf("unknown value '%s' for key '%s'", k, keyPurity)
+		}
+	}
+
+	var err error
+	if p.Tool, err = convert.Bool(m["tool"]); err != nil {
+		return Purity{}, fmt.Errorf("unable to convert 'tool' for key '%s' from JSON: %w", keyPurity, err)
+	}
+	if p.Patterns, err = convert.PatternList(m["patterns"], keyPurity+" patterns", data.EnumDollarNone, 0); err != nil {
+		return Purity{}, err
+	}
+	p.AllowedVars, err = convert.PatternList(m["allowedVars"], keyPurity+" allowedVars", data.EnumDollarNone, 0)
+	if err != nil {
+		return Purity{}, err
+	}
+	return p, nil
+}
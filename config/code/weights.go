@@ -0,0 +1,53 @@
+package code
+
+import (
+	"fmt"
+
+	"github.com/flowdev/spaghetti-cutter/config/convert"
+	"github.com/flowdev/spaghetti-cutter/x/astsize"
+)
+
+// SizeWeightsFromJSON converts the size weights of the given key.
+// Weights that aren't configured keep their default.
+func SizeWeightsFromJSON(i interface{}, keySizeWeights string) (astsize.Weights, error) {
+	sw := astsize.DefaultWeights()
+
+	if i == nil {
+		return sw, nil
+	}
+
+	m, ok := i.(map[string]interface{})
+	if !ok {
+		return astsize.Weights{}, fmt.Errorf("expected map for key '%s', got type: %T", keySizeWeights, i)
+	}
+
+	weights := map[string]*uint{
+		"ident":          &sw.Ident,
+		"basicLit":       &sw.BasicLit,
+		"basicLitLength": &sw.BasicLitLength,
+		"compositeLit":   &sw.CompositeLit,
+		"structType":     &sw.StructType,
+		"interfaceType":  &sw.InterfaceType,
+		"funcType":       &sw.FuncType,
+		"funcLit":        &sw.FuncLit,
+		"valueName":      &sw.ValueName,
+		"select":         &sw.Select,
+		"branch":         &sw.Branch,
+		"label":          &sw.Label,
+		"go":             &sw.Go,
+		"defer":          &sw.Defer,
+	}
+	for k, v := range m {
+		w, ok := weights[k]
+		if !ok {
+			return astsize.Weights{}, fmt.Errorf("unknown size weight '%s' for key '%s'", k, keySizeWeights)
+		}
+		u, err := convert.UInt(v)
+		if err != nil {
+			return astsize.Weights{}, fmt.Errorf("unable to convert size weight '%s' from JSON: %w", k, err)
+		}
+		*w = u
+	}
+
+	return sw, nil
+}
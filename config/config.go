@@ -2,10 +2,16 @@ package config
 
 import (
 	"fmt"
-	"math"
-	"regexp"
 
+	"github.com/flowdev/spaghetti-cutter/config/code"
+	"github.com/flowdev/spaghetti-cutter/config/convert"
+	"github.com/flowdev/spaghetti-cutter/config/imports"
+	"github.com/flowdev/spaghetti-cutter/config/location"
+	"github.com/flowdev/spaghetti-cutter/config/preset"
+	"github.com/flowdev/spaghetti-cutter/config/types"
 	"github.com/flowdev/spaghetti-cutter/data"
+	"github.com/flowdev/spaghetti-cutter/x/astsize"
+	"github.com/flowdev/spaghetti-cutter/x/pkgs"
 	"github.com/hjson/hjson-go"
 )
 
@@ -21,191 +27,305 @@ type Config struct {
 	God               data.PatternList
 	Size              uint
 	NoGod             bool
+	SizeWeights       SizeWeights
+	Generated         data.PatternList
+	GeneratedFiles    GeneratedFiles
+	Exclude           data.PatternList
+	Deny              *data.PatternMap
+	DenyTransitive    *data.PatternMap
+	Layers            Layers
+	StrictLayers      bool
+	Types             TypeDefs
+	FanLimits         FanLimits
+	Components        Components
+	Facades           *data.PatternMap
+	ExternalAllowed   data.PatternList // nil: all external packages are allowed
+	TestOnly          data.PatternList
+	Tests             Tests
+	AllowCallOnlyIn   *data.PatternMap
+	Concurrency       Concurrency
+	Purity            Purity
 }
 
+// The parts of the configuration are defined in sub-packages. These type
+// aliases let other packages use them without importing the sub-packages.
+type (
+	// SizeWeights contains the weights of the main node categories that are
+	// used for computing the size of a package.
+	SizeWeights = astsize.Weights
+	// TypeDef defines a package type (see: types.TypeDef).
+	TypeDef = types.TypeDef
+	// TypeDefs are the package types defined in the configuration.
+	TypeDefs = types.TypeDefs
+	// TypeInfo contains the type of a package and the configuration that
+	// determined it.
+	TypeInfo = types.Info
+	// Layer is a named group of packages.
+	Layer = imports.Layer
+	// Layers are ordered from top to bottom.
+	Layers = imports.Layers
+	// Component is a named group of packages that is developed as a unit.
+	Component = imports.Component
+	// Components are the components in the order of the configuration.
+	Components = imports.Components
+	// FanLimit limits the fan-in and fan-out of the matching packages.
+	FanLimit = imports.FanLimit
+	// FanLimits are the fan-in and fan-out limits.
+	FanLimits = imports.FanLimits
+	// Tests contains the import policy of test packages.
+	Tests = imports.Tests
+	// Concurrency contains the packages that may use concurrency.
+	Concurrency = code.Concurrency
+	// Purity contains the packages that have to be free of side effects.
+	Purity = code.Purity
+	// GeneratedMode tells a check how to handle generated files.
+	GeneratedMode = code.GeneratedMode
+	// GeneratedFiles contains the modes for handling generated files per
+	// check.
+	GeneratedFiles = code.GeneratedFiles
+)
+
+// Enum of modes for generated files: normal, ignore and separate
 const (
+	GeneratedNormal   = code.GeneratedNormal
+	GeneratedIgnore   = code.GeneratedIgnore
+	GeneratedSeparate = code.GeneratedSeparate
+)
+
+// AllTypes can be used in MayImport to allow imports of all types.
+const AllTypes = types.All
+
+// TypeLabel returns the designation of packages of the given type used in
+// error messages.
+func TypeLabel(t pkgs.PkgType) string {
+	return types.Label(t)
+}
+
+// ContainsType returns true if the type names contain the given type or
+// AllTypes.
+func ContainsType(names []string, t pkgs.PkgType) bool {
+	return types.Contains(names, t)
+}
+
+// TypeNames returns the given type names quoted for messages,
+// or "..." if there are none.
+func TypeNames(names []string) string {
+	return types.Names(names)
+}
+
+// FacadeRoot returns the pattern of the root package of the module that is
+// protected by the given facade key (e.g. `pkg/$*` for `pkg/$*/**`).
+func FacadeRoot(key string) string {
+	return imports.FacadeRoot(key)
+}
+
+// AllTypeDefs returns the package types defined in the configuration followed
+// by the built-in types (see: types.TypeDefs.WithBuiltins).
+func (cfg Config) AllTypeDefs() TypeDefs {
+	return cfg.Types.WithBuiltins(cfg.God, cfg.Generated, cfg.DB, cfg.Tool, cfg.TestOnly)
+}
+
+// ChecksAPI returns true if any package type restricts the types its packages
+// may expose in their API (`mayNotExpose` or `mayNotExposePackages`).
+func (cfg Config) ChecksAPI() bool {
+	for _, td := range cfg.Types {
+		if len(td.MayNotExpose) > 0 || len(td.MayNotExposePackages) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Keys of the configuration.
+const (
+	keyPreset            = "preset"
 	keyAllowOnlyIn       = "allowOnlyIn"
 	keyAllowAdditionally = "allowAdditionally"
+	keyDeny              = "deny"
+	keyDenyTransitive    = "denyTransitive"
+	keyLayers            = "layers"
+	keyStrictLayers      = "strictLayers"
+	keyTypes             = "types"
+	keyFanLimits         = "fanLimits"
+	keyComponents        = "components"
+	keyFacades           = "facades"
+	keyExternalAllowed   = "externalAllowed"
+	keyTestOnly          = "testOnly"
+	keyTests             = "tests"
+	keyAllowCallOnlyIn   = "allowCallOnlyIn"
+	keyConcurrency       = "concurrency"
+	keyPurity            = "purity"
 	keyTool              = "tool"
 	keyDB                = "db"
 	keyGod               = "god"
 	keySize              = "size"
+	keySizeWeights       = "sizeWeights"
 	keyNoGod             = "noGod"
+	keyGenerated         = "generated"
+	keyGeneratedFiles    = "generatedFiles"
+	keyExclude           = "exclude"
 )
 
 type jsonConfig struct {
 	AllowOnlyIn       map[string][]string `json:"allowOnlyIn,omitempty"`
 	AllowAdditionally map[string][]string `json:"allowAdditionally,omitempty"`
+	Deny              map[string][]string `json:"deny,omitempty"`
+	DenyTransitive    map[string][]string `json:"denyTransitive,omitempty"`
+	Layers            []jsonLayer         `json:"layers,omitempty"`
+	StrictLayers      bool                `json:"strictLayers,omitempty"`
+	Types             []jsonTypeDef       `json:"types,omitempty"`
+	FanLimits         []jsonFanLimit      `json:"fanLimits,omitempty"`
+	Components        []jsonComponent     `json:"components,omitempty"`
+	Facades           map[string][]string `json:"facades,omitempty"`
+	ExternalAllowed   []string            `json:"externalAllowed,omitempty"`
+	TestOnly          []string            `json:"testOnly,omitempty"`
+	Tests             *jsonTests          `json:"tests,omitempty"`
+	AllowCallOnlyIn   map[string][]string `json:"allowCallOnlyIn,omitempty"`
+	Concurrency       *jsonConcurrency    `json:"concurrency,omitempty"`
+	Purity            *jsonPurity         `json:"purity,omitempty"`
 	Tool              []string            `json:"tool,omitempty"`
 	DB                []string            `json:"db,omitempty"`
 	God               []string            `json:"god,omitempty"`
 	Size              uint                `json:"size,omitempty"`
+	SizeWeights       map[string]uint     `json:"sizeWeights,omitempty"`
+	Generated         []string            `json:"generated,omitempty"`
+	GeneratedFiles    map[string]string   `json:"generatedFiles,omitempty"`
+	Exclude           []string            `json:"exclude,omitempty"`
 	NoGod             bool                `json:"noGod,omitempty"`
 }
 
-func convertFromJSON(jcfg map[string]interface{}) (Config, error) {
-	var err error
-	var size uint
-	var noGod bool
-	var pl data.PatternList
-	var pm *data.PatternMap
+type jsonLayer struct {
+	Name     string   `json:"name"`
+	Patterns []string `json:"patterns"`
+}
 
-	cfg := Config{}
+type jsonFanLimit struct {
+	Patterns          []string `json:"patterns"`
+	MaxFanIn          uint     `json:"maxFanIn,omitempty"`
+	MaxFanOut         uint     `json:"maxFanOut,omitempty"`
+	MaxExternalFanOut uint     `json:"maxExternalFanOut,omitempty"`
+}
 
-	if size, err = convertUIntFromJSON(jcfg[keySize]); err != nil {
-		return Config{}, fmt.Errorf("unable to convert maximum package size from JSON: %w", err)
-	}
-	cfg.Size = size
+type jsonComponent struct {
+	Name      string   `json:"name"`
+	Patterns  []string `json:"patterns"`
+	MayImport []string `json:"mayImport,omitempty"`
+}
 
-	if noGod, err = convertBoolFromJSON(jcfg[keyNoGod]); err != nil {
-		return Config{}, fmt.Errorf("unable to convert no-god flag from JSON: %w", err)
-	}
-	cfg.NoGod = noGod
+type jsonTests struct {
+	MayImport []string `json:"mayImport,omitempty"`
+	InPackage bool     `json:"inPackage,omitempty"`
+}
 
-	if pm, err = convertPatternMapFromJSON(jcfg[keyAllowOnlyIn], keyAllowOnlyIn); err != nil {
-		return cfg, err
-	}
-	cfg.AllowOnlyIn = pm
+type jsonConcurrency struct {
+	AllowedIn []string `json:"allowedIn"`
+	Channels  bool     `json:"channels,omitempty"`
+}
 
-	if pm, err = convertPatternMapFromJSON(jcfg[keyAllowAdditionally], keyAllowAdditionally); err != nil {
-		return cfg, err
-	}
-	cfg.AllowAdditionally = pm
+type jsonPurity struct {
+	Tool        bool     `json:"tool,omitempty"`
+	Patterns    []string `json:"patterns,omitempty"`
+	AllowedVars []string `json:"allowedVars,omitempty"`
+}
 
-	if pl, err = convertPatternListFromJSON(jcfg[keyTool], keyTool, data.EnumDollarNone, 0); err != nil {
-		return cfg, err
-	}
-	cfg.Tool = pl
+type jsonTypeDef struct {
+	Name                 string   `json:"name"`
+	Patterns             []string `json:"patterns,omitempty"`
+	MayImport            []string `json:"mayImport,omitempty"`
+	MayBeImportedBy      []string `json:"mayBeImportedBy,omitempty"`
+	ForbiddenImports     []string `json:"forbiddenImports,omitempty"`
+	MayNotExpose         []string `json:"mayNotExpose,omitempty"`
+	MayNotExposePackages []string `json:"mayNotExposePackages,omitempty"`
+}
 
-	if pl, err = convertPatternListFromJSON(jcfg[keyDB], keyDB, data.EnumDollarNone, 0); err != nil {
-		return cfg, err
-	}
-	cfg.DB = pl
+// patternList connects a simple pattern list of the configuration with its key.
+type patternList struct {
+	key string
+	pl  *data.PatternList
+}
 
-	if pl, err = convertPatternListFromJSON(jcfg[keyGod], keyGod, data.EnumDollarNone, 0); err != nil {
-		return cfg, err
+func (cfg *Config) patternLists() []patternList {
+	return []patternList{
+		{keyTool, &cfg.Tool}, {keyDB, &cfg.DB}, {keyGod, &cfg.God}, {keyGenerated, &cfg.Generated},
+		{keyExclude, &cfg.Exclude}, {keyExternalAllowed, &cfg.ExternalAllowed}, {keyTestOnly, &cfg.TestOnly},
 	}
-	cfg.God = pl
-
-	return cfg, nil
 }
 
-func convertPatternMapFromJSON(i interface{}, key string) (*data.PatternMap, error) {
-	var err error
-	var pl data.PatternList
-	var re *regexp.Regexp
-	var dollars int
-
-	if i == nil {
-		return nil, nil
-	}
+// patternMap connects a pattern map of the configuration with its key.
+type patternMap struct {
+	key string
+	pm  **data.PatternMap
+}
 
-	m, ok := i.(map[string]interface{})
-	if !ok {
-		return nil, fmt.Errorf("expected string map for key '%s', got type: %T", key, i)
+// patternMaps returns the pattern maps of the configuration except the
+// facades because they are checked on their own.
+func (cfg *Config) patternMaps() []patternMap {
+	return []patternMap{
+		{keyAllowOnlyIn, &cfg.AllowOnlyIn}, {keyAllowAdditionally, &cfg.AllowAdditionally},
+		{keyDeny, &cfg.Deny}, {keyDenyTransitive, &cfg.DenyTransitive}, {keyAllowCallOnlyIn, &cfg.AllowCallOnlyIn},
 	}
+}
 
-	pm := data.PatternMap(make(map[string]data.PatternGroup, len(m)))
+func convertFromJSON(jcfg map[string]interface{}) (Config, error) {
+	var err error
+	cfg := Config{}
 
-	for k, v := range m {
-		if re, dollars, _, err = data.RegexpForPattern(k, data.EnumDollarStar, 0); err != nil {
-			return nil, fmt.Errorf("illegal left/key pattern %q for global key '%s': %w", k, key, err)
-		}
-		if pl, err = convertPatternListFromJSON(v, key+": "+k, data.EnumDollarDigit, dollars); err != nil {
-			return nil, err
-		}
-		pm[k] = data.PatternGroup{
-			Left:  data.Pattern{Pattern: k, Regexp: re},
-			Right: pl,
-		}
+	if cfg.Size, err = convert.UInt(jcfg[keySize]); err != nil {
+		return cfg, fmt.Errorf("unable to convert maximum package size from JSON: %w", err)
 	}
-
-	return &pm, nil
-}
-
-func convertPatternListFromJSON(i interface{}, key string, allowDollar data.EnumDollar, keyDollars int) (data.PatternList, error) {
-	if i == nil {
-		return nil, nil
+	if cfg.SizeWeights, err = code.SizeWeightsFromJSON(jcfg[keySizeWeights], keySizeWeights); err != nil {
+		return cfg, err
 	}
-
-	sl, ok := i.([]interface{})
-	if !ok {
-		return nil, fmt.Errorf("expected string list for key '%s', got type: %T", key, i)
+	if cfg.NoGod, err = convert.Bool(jcfg[keyNoGod]); err != nil {
+		return cfg, fmt.Errorf("unable to convert no-god flag from JSON: %w", err)
 	}
-
-	l := make([]data.Pattern, len(sl))
-	for i, v := range sl {
-		s, err := convertStringFromJSON(v)
-		if err != nil {
-			return nil, fmt.Errorf("unable to convert list for key '%s' from JSON: %w", key, err)
-		}
-		re, _, dollarIdxs, err := data.RegexpForPattern(s, allowDollar, keyDollars)
-		if err != nil {
-			return nil, fmt.Errorf("unable to use pattern `%s` of key '%s': %w", s, key, err)
+	for _, m := range cfg.patternMaps() {
+		// only the exceptions are checked for expired entries
+		withExpiry := m.key == keyAllowOnlyIn || m.key == keyAllowAdditionally
+		if *m.pm, err = convert.PatternMap(jcfg[m.key], m.key, withExpiry); err != nil {
+			return cfg, err
 		}
-		l[i] = data.Pattern{Pattern: s, Regexp: re, DollarIdxs: dollarIdxs}
 	}
-	return data.PatternList(l), nil
-}
-
-func convertUIntFromJSON(i interface{}) (uint, error) {
-	var f float64
-	var ok bool
-
-	if i == nil {
-		return 0, nil
+	if cfg.Layers, err = imports.LayersFromJSON(jcfg[keyLayers], keyLayers); err != nil {
+		return cfg, err
 	}
-
-	if f, ok = i.(float64); !ok {
-		return 0, fmt.Errorf("expected positive integer value, got type: %T", i)
+	if cfg.StrictLayers, err = convert.Bool(jcfg[keyStrictLayers]); err != nil {
+		return cfg, fmt.Errorf("unable to convert strict layers flag from JSON: %w", err)
 	}
-
-	if f < 0.0 {
-		return 0, fmt.Errorf("expected positive integer value, got negative: %f", f)
+	if cfg.Types, err = types.FromJSON(jcfg[keyTypes], keyTypes); err != nil {
+		return cfg, err
 	}
-
-	if f > math.MaxUint32 {
-		return 0, fmt.Errorf("expected unsigned integer value, got too large: %f", f)
+	if cfg.Concurrency, err = code.ConcurrencyFromJSON(jcfg[keyConcurrency], keyConcurrency); err != nil {
+		return cfg, err
 	}
-
-	if f != math.Trunc(f) {
-		return 0, fmt.Errorf("expected unsigned integer value, got float: %f", f)
+	if cfg.Purity, err = code.PurityFromJSON(jcfg[keyPurity], keyPurity); err != nil {
+		return cfg, err
 	}
-
-	return uint(f), nil
-}
-
-func convertBoolFromJSON(i interface{}) (bool, error) {
-	var b, ok bool
-
-	if i == nil {
-		return false, nil
+	if cfg.Tests, err = imports.TestsFromJSON(jcfg[keyTests], keyTests, cfg.Types); err != nil {
+		return cfg, err
 	}
-
-	if b, ok = i.(bool); !ok {
-		return false, fmt.Errorf("expected boolean value, got type: %T", i)
+	if cfg.FanLimits, err = imports.FanLimitsFromJSON(jcfg[keyFanLimits], keyFanLimits); err != nil {
+		return cfg, err
 	}
-
-	return b, nil
-}
-
-func convertStringFromJSON(i interface{}) (string, error) {
-	var s string
-	var ok bool
-
-	if i == nil {
-		return "", nil
+	if cfg.Components, err = imports.ComponentsFromJSON(jcfg[keyComponents], keyComponents); err != nil {
+		return cfg, err
 	}
-
-	if s, ok = i.(string); !ok {
-		return "", fmt.Errorf("expected string value, got type: %T", i)
+	if cfg.Facades, err = imports.FacadesFromJSON(jcfg[keyFacades], keyFacades); err != nil {
+		return cfg, err
 	}
-
-	return s, nil
+	for _, l := range cfg.patternLists() {
+		if *l.pl, err = convert.PatternList(jcfg[l.key], l.key, data.EnumDollarNone, 0); err != nil {
+			return cfg, err
+		}
+	}
+	if cfg.GeneratedFiles, err = code.GeneratedFilesFromJSON(jcfg[keyGeneratedFiles], keyGeneratedFiles); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
 }
 
 // Parse parses the configuration bytes and uses cfgFile only for better error
-// messages.
+// messages and the locations of the patterns.
 func Parse(cfgBytes []byte, cfgFile string) (Config, error) {
 	cfg := Config{}
 	var jsonCfg map[string]interface{}
@@ -213,21 +333,45 @@ func Parse(cfgBytes []byte, cfgFile string) (Config, error) {
 	if err := hjson.Unmarshal(cfgBytes, &jsonCfg); err != nil {
 		return Config{}, fmt.Errorf("unable to unmarshal JSON configuration from file %q: %w", cfgFile, err)
 	}
+	presetName, _ := convert.String(jsonCfg[keyPreset])
+	jsonCfg, err := preset.Expand(jsonCfg)
+	if err != nil {
+		return Config{}, err
+	}
 
-	noGod, _ := convertBoolFromJSON(jsonCfg[keyNoGod])
-	god, _ := convertPatternListFromJSON(jsonCfg[keyGod], keyGod, data.EnumDollarNone, 0)
+	noGod, _ := convert.Bool(jsonCfg[keyNoGod])
+	god, _ := convert.PatternList(jsonCfg[keyGod], keyGod, data.EnumDollarNone, 0)
 	if !noGod && (god == nil || len(god) == 0) {
 		jsonCfg[keyGod] = []interface{}{"main"} // default
 	}
 
-	if size, _ := convertUIntFromJSON(jsonCfg[keySize]); size == 0 {
+	if size, _ := convert.UInt(jsonCfg[keySize]); size == 0 {
 		jsonCfg[keySize] = 2048.0
 	}
 
-	cfg, err := convertFromJSON(jsonCfg)
+	cfg, err = convertFromJSON(jsonCfg)
 	if err != nil {
 		return Config{}, err
 	}
+	loc := location.Locator{File: cfgFile, Text: string(cfgBytes)}
+	if presetName != "" {
+		loc.Fallback = "preset " + presetName
+	}
+	locate(loc, &cfg)
 
 	return cfg, nil
 }
+
+// Show returns the configuration with its preset expanded in HJSON format.
+func Show(cfgBytes []byte, cfgFile string) ([]byte, error) {
+	var jsonCfg map[string]interface{}
+
+	if err := hjson.Unmarshal(cfgBytes, &jsonCfg); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal JSON configuration from file %q: %w", cfgFile, err)
+	}
+	jsonCfg, err := preset.Expand(jsonCfg)
+	if err != nil {
+		return nil, err
+	}
+	return hjson.Marshal(jsonCfg)
+}
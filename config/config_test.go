@@ -5,8 +5,17 @@ import (
 	"testing"
 
 	"github.com/flowdev/spaghetti-cutter/config"
+	"github.com/flowdev/spaghetti-cutter/data"
 )
 
+// defaultTail is the string representation of all configuration values that
+// are printed after the 'noGod' flag and aren't set explicitly.
+const defaultTail = " " + defaultSizeWeights + " ... {normal normal} ... ..... ..... ... false ... ... ... ..... ... ... -> * inPackage: false ..... allowedIn: ... channels: false tool: false patterns: ... allowedVars: ..."
+
+const defaultSizeWeights = "`ident`: 1 ; `basicLit`: 1 ; `basicLitLength`: 32 ; `compositeLit`: 0 ; " +
+	"`structType`: 1 ; `interfaceType`: 1 ; `funcType`: 1 ; `funcLit`: 0 ; `valueName`: 1 ; " +
+	"`select`: 1 ; `branch`: 1 ; `label`: 1 ; `go`: 1 ; `defer`: 1"
+
 func TestParseAndStringers(t *testing.T) {
 	specs := []struct {
 		name                 string
@@ -20,7 +29,7 @@ func TestParseAndStringers(t *testing.T) {
 			expectedConfigString: "{" +
 				"..... ..... ... ... `main` " +
 				"2048 false" +
-				"}",
+				defaultTail + "}",
 		}, {
 			name: "scalars-only",
 			givenConfigBytes: []byte(`{
@@ -30,7 +39,7 @@ func TestParseAndStringers(t *testing.T) {
 			expectedConfigString: "{" +
 				"..... ..... ... ... ... " +
 				"3072 true" +
-				"}",
+				defaultTail + "}",
 		}, {
 			name: "list-one",
 			givenConfigBytes: []byte(`{
@@ -42,7 +51,7 @@ func TestParseAndStringers(t *testing.T) {
 				"... " +
 				"`a` " +
 				"2048 false" +
-				"}",
+				defaultTail + "}",
 		}, {
 			name: "list-many",
 			givenConfigBytes: []byte(`{
@@ -54,7 +63,7 @@ func TestParseAndStringers(t *testing.T) {
 				"... " +
 				"`a`, `be`, `do`, `ra` " +
 				"2048 false" +
-				"}",
+				defaultTail + "}",
 		}, {
 			name: "map-simple-pair",
 			givenConfigBytes: []byte(`{
@@ -66,7 +75,7 @@ func TestParseAndStringers(t *testing.T) {
 				"`a`: `b` " +
 				"..... " +
 				"... ... `main` 2048 false" +
-				"}",
+				defaultTail + "}",
 		}, {
 			name: "map-multiple-pairs",
 			givenConfigBytes: []byte(`{
@@ -79,7 +88,7 @@ func TestParseAndStringers(t *testing.T) {
 				"`a`: `b`, `c`, `do`, `foo` ; `e`: `bar`, `car` " +
 				"..... " +
 				"... ... `main` 2048 false" +
-				"}",
+				defaultTail + "}",
 		}, {
 			name: "map-one-pair-many-stars",
 			givenConfigBytes: []byte(`{
@@ -91,7 +100,7 @@ func TestParseAndStringers(t *testing.T) {
 				"`a/*/b/**`: `c/*/d/**` " +
 				"..... " +
 				"... ... `main` 2048 false" +
-				"}",
+				defaultTail + "}",
 		}, {
 			name: "map-all-complexity",
 			givenConfigBytes: []byte(`{
@@ -103,7 +112,7 @@ func TestParseAndStringers(t *testing.T) {
 				"..... " +
 				"`*/*a/**`: `*/*b/**`, `b*/c*d/**` " +
 				"... ... `main` 2048 false" +
-				"}",
+				defaultTail + "}",
 		}, {
 			name: "maps-and-lists-only",
 			givenConfigBytes: []byte(`{
@@ -125,7 +134,7 @@ func TestParseAndStringers(t *testing.T) {
 				"`main` " +
 				"2048 " +
 				"false" +
-				"}",
+				defaultTail + "}",
 		}, {
 			name: "a-bit-of-everything",
 			givenConfigBytes: []byte(`{
@@ -150,6 +159,250 @@ func TestParseAndStringers(t *testing.T) {
 				"`main`, `pkg/service` " +
 				"3072 " +
 				"true" +
+				defaultTail + "}",
+		}, {
+			name: "size-weights",
+			givenConfigBytes: []byte(`{
+					"sizeWeights": {
+					  "basicLitLength": 0,
+					  "compositeLit": 2,
+					  "go": 3
+					}
+				}`),
+			expectedConfigString: "{" +
+				"..... ..... ... ... `main` " +
+				"2048 false " +
+				"`ident`: 1 ; `basicLit`: 1 ; `basicLitLength`: 0 ; `compositeLit`: 2 ; " +
+				"`structType`: 1 ; `interfaceType`: 1 ; `funcType`: 1 ; `funcLit`: 0 ; `valueName`: 1 ; " +
+				"`select`: 1 ; `branch`: 1 ; `label`: 1 ; `go`: 3 ; `defer`: 1" +
+				" ... {normal normal} ... ..... ..... ... false ... ... ... ..... ... ... -> * inPackage: false ..... allowedIn: ... channels: false tool: false patterns: ... allowedVars: ..." +
+				"}",
+		}, {
+			name: "generated",
+			givenConfigBytes: []byte(`{
+					"generated": ["pkg/pb/*", "pkg/mocks/**"],
+					"generatedFiles": {
+					  "size": "ignore",
+					  "deps": "separate"
+					}
+				}`),
+			expectedConfigString: "{" +
+				"..... ..... ... ... `main` " +
+				"2048 false " + defaultSizeWeights + " " +
+				"`pkg/pb/*`, `pkg/mocks/**` " +
+				"{ignore separate} ... ..... ..... ... false ... ... ... ..... ... ... -> * inPackage: false ..... allowedIn: ... channels: false tool: false patterns: ... allowedVars: ..." +
+				"}",
+		}, {
+			name: "exclude",
+			givenConfigBytes: []byte(`{
+					"exclude": ["examples/**", "tools"]
+				}`),
+			expectedConfigString: "{" +
+				"..... ..... ... ... `main` " +
+				"2048 false " + defaultSizeWeights + " ... {normal normal} " +
+				"`examples/**`, `tools` ..... ..... ... false ... ... ... ..... ... ... -> * inPackage: false ..... allowedIn: ... channels: false tool: false patterns: ... allowedVars: ..." +
+				"}",
+		}, {
+			name: "deny",
+			givenConfigBytes: []byte(`{
+					"deny": {"cmd/$*": ["pkg/db/**", {"pattern": "pkg/$1", "reason": "commands have to stay independent"}]}
+				}`),
+			expectedConfigString: "{" +
+				"..... ..... ... ... `main` " +
+				"2048 false " + defaultSizeWeights + " ... {normal normal} ... " +
+				"`cmd/$*`: `pkg/db/**`, `pkg/$1` ..... ... false ... ... ... ..... ... ... -> * inPackage: false ..... allowedIn: ... channels: false tool: false patterns: ... allowedVars: ..." +
+				"}",
+		}, {
+			name: "deny-transitive",
+			givenConfigBytes: []byte(`{
+					"denyTransitive": {"pkg/domain/**": ["database/sql", {"pattern": "net/http", "reason": "the domain is independent of the web"}]}
+				}`),
+			expectedConfigString: "{" +
+				"..... ..... ... ... `main` " +
+				"2048 false " + defaultSizeWeights + " ... {normal normal} ... ..... " +
+				"`pkg/domain/**`: `database/sql`, `net/http` ... false ... ... ... ..... ... ... -> * inPackage: false ..... allowedIn: ... channels: false tool: false patterns: ... allowedVars: ..." +
+				"}",
+		}, {
+			name: "layers",
+			givenConfigBytes: []byte(`{
+					"layers": [
+						{"name": "adapters", "patterns": ["adapter/*", "cmd/**"]},
+						{"name": "domain", "patterns": ["domain/**"]}
+					],
+					"strictLayers": true
+				}`),
+			expectedConfigString: "{" +
+				"..... ..... ... ... `main` " +
+				"2048 false " + defaultSizeWeights + " ... {normal normal} ... ..... ..... " +
+				"`adapters`: `adapter/*`, `cmd/**` > `domain`: `domain/**` true ... ... ... ..... ... ... -> * inPackage: false ..... allowedIn: ... channels: false tool: false patterns: ... allowedVars: ..." +
+				"}",
+		}, {
+			name: "types",
+			givenConfigBytes: []byte(`{
+					"types": [
+						{"name": "adapter", "patterns": ["pkg/adapter/*"], "mayImport": ["port", "tool"]},
+						{"name": "port", "patterns": ["pkg/port/*"], "mayBeImportedBy": ["adapter", "god"]},
+						{"name": "standard", "mayImport": ["tool", "db", "port"]}
+					]
+				}`),
+			expectedConfigString: "{" +
+				"..... ..... ... ... `main` " +
+				"2048 false " + defaultSizeWeights + " ... {normal normal} ... ..... ..... ... false " +
+				"`adapter`: `pkg/adapter/*` -> `port`, `tool` <- ... ; " +
+				"`port`: `pkg/port/*` -> ... <- `adapter`, `god` ; " +
+				"`standard`: ... -> `tool`, `db`, `port` <- ... ... ... ..... ... ... -> * inPackage: false ..... allowedIn: ... channels: false tool: false patterns: ... allowedVars: ..." +
+				"}",
+		}, {
+			name: "forbidden-imports",
+			givenConfigBytes: []byte(`{
+					"types": [
+						{"name": "domain", "patterns": ["pkg/domain/*"], "mayImport": ["tool"], "forbiddenImports": ["std:net/**", "ext:**"]},
+						{"name": "tool", "forbiddenImports": ["std:os"]}
+					]
+				}`),
+			expectedConfigString: "{" +
+				"..... ..... ... ... `main` " +
+				"2048 false " + defaultSizeWeights + " ... {normal normal} ... ..... ..... ... false " +
+				"`domain`: `pkg/domain/*` -> `tool` <- ... !> `std:net/**`, `ext:**` ; " +
+				"`tool`: ... -> ... <- ... !> `std:os` ... ... ..... ... ... -> * inPackage: false ..... allowedIn: ... channels: false tool: false patterns: ... allowedVars: ..." +
+				"}",
+		}, {
+			name: "may-not-expose",
+			givenConfigBytes: []byte(`{
+					"types": [
+						{"name": "standard", "mayNotExpose": ["db"], "mayNotExposePackages": ["std:database/sql"]}
+					]
+				}`),
+			expectedConfigString: "{" +
+				"..... ..... ... ... `main` " +
+				"2048 false " + defaultSizeWeights + " ... {normal normal} ... ..... ..... ... false " +
+				"`standard`: ... -> ... <- ... !api `db` `std:database/sql` " +
+				"... ... ..... ... ... -> * inPackage: false ..... allowedIn: ... channels: false tool: false patterns: ... allowedVars: ..." +
+				"}",
+		}, {
+			name: "fan-limits",
+			givenConfigBytes: []byte(`{
+					"fanLimits": [
+						{"patterns": ["pkg/x/*"], "maxFanOut": 2},
+						{"patterns": ["pkg/**", "cmd/*"], "maxFanIn": 10, "maxFanOut": 15, "maxExternalFanOut": 5}
+					]
+				}`),
+			expectedConfigString: "{" +
+				"..... ..... ... ... `main` " +
+				"2048 false " + defaultSizeWeights + " ... {normal normal} ... ..... ..... ... false ... " +
+				"`pkg/x/*`: in 0, out 2, external out 0 ; " +
+				"`pkg/**`, `cmd/*`: in 10, out 15, external out 5 ... ..... ... ... -> * inPackage: false ..... allowedIn: ... channels: false tool: false patterns: ... allowedVars: ..." +
+				"}",
+		}, {
+			name: "components",
+			givenConfigBytes: []byte(`{
+					"components": [
+						{"name": "orders", "patterns": ["pkg/orders/**"], "mayImport": ["billing"]},
+						{"name": "billing", "patterns": ["pkg/billing/**"], "mayImport": []},
+						{"name": "ui", "patterns": ["cmd/**"]}
+					]
+				}`),
+			expectedConfigString: "{" +
+				"..... ..... ... ... `main` " +
+				"2048 false " + defaultSizeWeights + " ... {normal normal} ... ..... ..... ... false ... ... " +
+				"`orders`: `pkg/orders/**` -> `billing` ; " +
+				"`billing`: `pkg/billing/**` -> ... ; " +
+				"`ui`: `cmd/**` -> * ..... ... ... -> * inPackage: false ..... allowedIn: ... channels: false tool: false patterns: ... allowedVars: ..." +
+				"}",
+		}, {
+			name: "preset-web-api-extended",
+			givenConfigBytes: []byte(`{
+					"preset": "web-api",
+					"tool": ["lib/*"],
+					"size": 1024
+				}`),
+			expectedConfigString: "{" +
+				"..... ..... `x/*`, `pkg/x/*`, `internal/x/*`, `lib/*` `db/*`, `pkg/db/*`, `internal/db/*` `main`, `cmd/*` " +
+				"1024 false" +
+				defaultTail + "}",
+		}, {
+			name: "preset-web-api-no-god",
+			givenConfigBytes: []byte(`{
+					"preset": "web-api",
+					"noGod": true
+				}`),
+			expectedConfigString: "{" +
+				"..... ..... `x/*`, `pkg/x/*`, `internal/x/*` `db/*`, `pkg/db/*`, `internal/db/*` ... " +
+				"2048 true" +
+				defaultTail + "}",
+		}, {
+			name: "preset-web-api-no-god-own-god",
+			givenConfigBytes: []byte(`{
+					"preset": "web-api",
+					"noGod": true,
+					"god": ["cmd/admin"]
+				}`),
+			expectedConfigString: "{" +
+				"..... ..... `x/*`, `pkg/x/*`, `internal/x/*` `db/*`, `pkg/db/*`, `internal/db/*` `cmd/admin` " +
+				"2048 true" +
+				defaultTail + "}",
+		}, {
+			name: "map-with-metadata",
+			givenConfigBytes: []byte(`{
+					"allowAdditionally": {"a": ["b", {
+						"pattern": "c", "expires": "2021-12-31", "ticket": "PROJ-123", "owner": "team-a"
+					}]}
+				}`),
+			expectedConfigString: "{" +
+				"..... `a`: `b`, `c` ... ... `main` " +
+				"2048 false" +
+				defaultTail + "}",
+		}, {
+			name: "facades",
+			givenConfigBytes: []byte(`{
+					"facades": {"pkg/$*/**": ["pkg/$1/api", "pkg/$1/api/**"]}
+				}`),
+			expectedConfigString: "{" +
+				"..... ..... ... ... `main` " +
+				"2048 false " + defaultSizeWeights + " ... {normal normal} ... ..... ..... ... false ... ... ... " +
+				"`pkg/$*/**`: `pkg/$1/api`, `pkg/$1/api/**` ... ... -> * inPackage: false ..... allowedIn: ... channels: false tool: false patterns: ... allowedVars: ..." +
+				"}",
+		}, {
+			name: "tests",
+			givenConfigBytes: []byte(`{
+					"testOnly": ["pkg/testutil/**"],
+					"tests": {"mayImport": ["tool", "testOnly"], "inPackage": true}
+				}`),
+			expectedConfigString: "{" +
+				"..... ..... ... ... `main` " +
+				"2048 false " + defaultSizeWeights + " ... {normal normal} ... ..... ..... ... false ... ... ... ..... ... " +
+				"`pkg/testutil/**` -> `tool`, `testOnly` inPackage: true ..... allowedIn: ... channels: false tool: false patterns: ... allowedVars: ..." +
+				"}",
+		}, {
+			name: "allow-call-only-in",
+			givenConfigBytes: []byte(`{
+					"allowCallOnlyIn": {"os.Exit": ["main"], "log.Fatal*": ["main", "cmd/*"]}
+				}`),
+			expectedConfigString: "{" +
+				"..... ..... ... ... `main` " +
+				"2048 false " + defaultSizeWeights + " ... {normal normal} ... ..... ..... ... false ... ... ... ..... ... ... " +
+				"-> * inPackage: false `log.Fatal*`: `main`, `cmd/*` ; `os.Exit`: `main` allowedIn: ... channels: false tool: false patterns: ... allowedVars: ..." +
+				"}",
+		}, {
+			name: "concurrency",
+			givenConfigBytes: []byte(`{
+					"concurrency": {"allowedIn": ["pkg/x/worker", "cmd/*"], "channels": true}
+				}`),
+			expectedConfigString: "{" +
+				"..... ..... ... ... `main` " +
+				"2048 false " + defaultSizeWeights + " ... {normal normal} ... ..... ..... ... false ... ... ... ..... ... ... " +
+				"-> * inPackage: false ..... allowedIn: `pkg/x/worker`, `cmd/*` channels: true tool: false patterns: ... allowedVars: ..." +
+				"}",
+		}, {
+			name: "purity",
+			givenConfigBytes: []byte(`{
+					"purity": {"tool": true, "patterns": ["pkg/domain/**"], "allowedVars": ["Default*"]}
+				}`),
+			expectedConfigString: "{" +
+				"..... ..... ... ... `main` " +
+				"2048 false " + defaultSizeWeights + " ... {normal normal} ... ..... ..... ... false ... ... ... ..... ... ... " +
+				"-> * inPackage: false ..... allowedIn: ... channels: false " +
+				"tool: true patterns: `pkg/domain/**` allowedVars: `Default*`" +
 				"}",
 		},
 	}
@@ -168,3 +421,213 @@ func TestParseAndStringers(t *testing.T) {
 		})
 	}
 }
+
+func TestParseReasonsAndLocations(t *testing.T) {
+	cfgBytes := []byte(`{
+	// comments with tool: ["pkg/db/x"] don't confuse the search
+	"allowOnlyIn": {
+		"github.com/lib/pq": {
+			"reason": "only the DB layer talks to postgres"
+			"patterns": ["pkg/db/*"]
+		}
+	}
+	tool: [
+		"pkg/x/*", // a trailing comment with "pkg/db/x"
+		{"pattern": "pkg/db/x", "reason": "the DB helpers are tools, too"}
+	]
+	"deny": {"cmd/*": ["pkg/x/*", {"pattern": "pkg/db/**", "reason": "use the domain packages"}]}
+	"types": [
+		{"name": "port", "mayImport": ["adapter"]}
+		{"name": "adapter", "patterns": ["pkg/adapter/*"]}
+	]
+}`)
+	specs := []struct {
+		name             string
+		givenPattern     func(cfg config.Config) data.Pattern
+		expectedPattern  string
+		expectedReason   string
+		expectedLocation string
+	}{
+		{
+			name:             "allowOnlyIn-key",
+			givenPattern:     func(cfg config.Config) data.Pattern { return (*cfg.AllowOnlyIn)["github.com/lib/pq"].Left },
+			expectedPattern:  "github.com/lib/pq",
+			expectedReason:   "only the DB layer talks to postgres",
+			expectedLocation: "test.hjson:4",
+		}, {
+			name:             "allowOnlyIn-value",
+			givenPattern:     func(cfg config.Config) data.Pattern { return (*cfg.AllowOnlyIn)["github.com/lib/pq"].Right[0] },
+			expectedPattern:  "pkg/db/*",
+			expectedReason:   "",
+			expectedLocation: "test.hjson:6",
+		}, {
+			name:             "tool-simple",
+			givenPattern:     func(cfg config.Config) data.Pattern { return cfg.Tool[0] },
+			expectedPattern:  "pkg/x/*",
+			expectedReason:   "",
+			expectedLocation: "test.hjson:10",
+		}, {
+			name:             "tool-with-reason",
+			givenPattern:     func(cfg config.Config) data.Pattern { return cfg.Tool[1] },
+			expectedPattern:  "pkg/db/x",
+			expectedReason:   "the DB helpers are tools, too",
+			expectedLocation: "test.hjson:11",
+		}, {
+			name:             "deny-duplicate-of-tool",
+			givenPattern:     func(cfg config.Config) data.Pattern { return (*cfg.Deny)["cmd/*"].Right[0] },
+			expectedPattern:  "pkg/x/*",
+			expectedReason:   "",
+			expectedLocation: "test.hjson:13",
+		}, {
+			name:             "deny-with-reason",
+			givenPattern:     func(cfg config.Config) data.Pattern { return (*cfg.Deny)["cmd/*"].Right[1] },
+			expectedPattern:  "pkg/db/**",
+			expectedReason:   "use the domain packages",
+			expectedLocation: "test.hjson:13",
+		}, {
+			name: "type-pattern",
+			givenPattern: func(cfg config.Config) data.Pattern {
+				return cfg.Types[1].Patterns[0]
+			},
+			expectedPattern:  "pkg/adapter/*",
+			expectedReason:   "",
+			expectedLocation: "test.hjson:16",
+		}, {
+			name:             "default-god",
+			givenPattern:     func(cfg config.Config) data.Pattern { return cfg.God[0] },
+			expectedPattern:  "main",
+			expectedReason:   "",
+			expectedLocation: "",
+		},
+	}
+
+	cfg, err := config.Parse(cfgBytes, "test.hjson")
+	if err != nil {
+		t.Fatalf("got unexpected error: %v", err)
+	}
+	for _, spec := range specs {
+		t.Run(spec.name, func(t *testing.T) {
+			p := spec.givenPattern(cfg)
+			if p.Pattern != spec.expectedPattern {
+				t.Errorf("expected pattern %q, actual %q", spec.expectedPattern, p.Pattern)
+			}
+			if p.Reason != spec.expectedReason {
+				t.Errorf("expected reason %q, actual %q", spec.expectedReason, p.Reason)
+			}
+			if p.Location != spec.expectedLocation {
+				t.Errorf("expected location %q, actual %q", spec.expectedLocation, p.Location)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	specs := []struct {
+		name        string
+		givenConfig string
+	}{
+		{
+			name:        "facade-without-double-star",
+			givenConfig: `{"facades": {"pkg/$*": ["pkg/$1/api"]}}`,
+		}, {
+			name:        "facade-with-wildcard-root",
+			givenConfig: `{"facades": {"pkg/*/$*/**": ["pkg/$1/api"]}}`,
+		}, {
+			name:        "facade-with-unknown-variable",
+			givenConfig: `{"facades": {"pkg/$*/**": ["pkg/$2/api"]}}`,
+		}, {
+			name:        "tests-with-unknown-type",
+			givenConfig: `{"tests": {"mayImport": ["testutil"]}}`,
+		}, {
+			name:        "tests-with-unknown-value",
+			givenConfig: `{"tests": {"inPackages": true}}`,
+		}, {
+			name:        "deny-with-expiry",
+			givenConfig: `{"deny": {"a": [{"pattern": "b", "expires": "2000-01-01"}]}}`,
+		}, {
+			name:        "facades-with-owner",
+			givenConfig: `{"facades": {"pkg/$*/**": [{"pattern": "pkg/$1/api", "owner": "team-a"}]}}`,
+		}, {
+			name:        "may-not-expose-unknown-type",
+			givenConfig: `{"types": [{"name": "standard", "mayNotExpose": ["dbx"]}]}`,
+		},
+	}
+
+	for _, spec := range specs {
+		t.Run(spec.name, func(t *testing.T) {
+			_, err := config.Parse([]byte(spec.givenConfig), spec.name)
+			if err == nil {
+				t.Fatal("expected to receive error but didn't get one")
+			}
+			t.Logf("received expected error: %v", err)
+		})
+	}
+}
+
+func TestPresets(t *testing.T) {
+	specs := []struct {
+		name           string
+		givenConfig    string
+		expectedLayers string
+		expectedError  bool
+	}{
+		{
+			name:           "web-api",
+			givenConfig:    `{"preset": "web-api"}`,
+			expectedLayers: "",
+		}, {
+			name:           "hexagonal",
+			givenConfig:    `{"preset": "hexagonal"}`,
+			expectedLayers: "adapters application ports domain",
+		}, {
+			name:           "hexagonal-replaced-layer",
+			givenConfig:    `{"preset": "hexagonal", "layers": [{"name": "application", "patterns": ["uc/**"]}, {"name": "infra"}]}`,
+			expectedLayers: "adapters application(`uc/**`) ports domain infra",
+		}, {
+			name:           "clean",
+			givenConfig:    `{"preset": "clean"}`,
+			expectedLayers: "frameworks interface-adapters use-cases entities",
+		}, {
+			name:           "modular-monolith",
+			givenConfig:    `{"preset": "modular-monolith"}`,
+			expectedLayers: "",
+		}, {
+			name:          "unknown",
+			givenConfig:   `{"preset": "spaghetti"}`,
+			expectedError: true,
+		},
+	}
+
+	for _, spec := range specs {
+		t.Run(spec.name, func(t *testing.T) {
+			cfg, err := config.Parse([]byte(spec.givenConfig), spec.name)
+			if spec.expectedError {
+				if err == nil {
+					t.Fatal("expected to receive error but didn't get one")
+				}
+				t.Logf("received expected error: %v", err)
+				return
+			}
+			if err != nil {
+				t.Fatalf("got unexpected error: %v", err)
+			}
+			if _, err = config.Show([]byte(spec.givenConfig), spec.name); err != nil {
+				t.Fatalf("got unexpected error from Show: %v", err)
+			}
+
+			actualLayers := ""
+			for i, l := range cfg.Layers {
+				if i > 0 {
+					actualLayers += " "
+				}
+				actualLayers += l.Name
+				if len(l.Patterns) == 1 {
+					actualLayers += "(" + l.Patterns.String() + ")"
+				}
+			}
+			if actualLayers != spec.expectedLayers {
+				t.Errorf("expected layers %q, actual %q", spec.expectedLayers, actualLayers)
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+// Package convert converts the values of the JSON configuration into their
+// Go counterparts.
+package convert
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"time"
+
+	"github.com/flowdev/spaghetti-cutter/data"
+)
+
+// dateLayout is the layout of dates in the configuration file.
+const dateLayout = "2006-01-02"
+
+// PatternMap converts a key/value map of patterns.
+// Only the values of the exceptions (allowOnlyIn and allowAdditionally) may
+// have an expiry date (withExpiry) because only they are checked for expired
+// entries.
+func PatternMap(i interface{}, key string, withExpiry bool) (*data.PatternMap, error) {
+	var err error
+	var pl data.PatternList
+	var re *regexp.Regexp
+	var dollars int
+
+	if i == nil {
+		return nil, nil
+	}
+
+	m, ok := i.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected string map for key '%s', got type: %T", key, i)
+	}
+
+	pm := data.PatternMap(make(map[string]data.PatternGroup, len(m)))
+
+	for k, v := range m {
+		if re, dollars, _, err = data.RegexpForPattern(k, data.EnumDollarStar, 0); err != nil {
+			return nil, fmt.Errorf("illegal left/key pattern %q for global key '%s': %w", k, key, err)
+		}
+		reason := ""
+		if group, ok := v.(map[string]interface{}); ok { // {"reason": "...", "patterns": [...]}
+			for gk := range group {
+				if gk != "reason" && gk != "patterns" {
+					return nil, fmt.Errorf("unknown value '%s' for key '%s: %s'", gk, key, k)
+				}
+			}
+			if reason, err = String(group["reason"]); err != nil {
+				return nil, fmt.Errorf("unable to convert reason for key '%s: %s' from JSON: %w", key, k, err)
+			}
+			v = group["patterns"]
+		}
+		if pl, err = Patterns(v, key+": "+k, data.EnumDollarDigit, dollars, withExpiry); err != nil {
+			return nil, err
+		}
+		pm[k] = data.PatternGroup{
+			Left:  data.Pattern{Pattern: k, Regexp: re, Reason: reason},
+			Right: pl,
+		}
+	}
+
+	return &pm, nil
+}
+
+// PatternList converts a list of patterns without expiry dates.
+func PatternList(i interface{}, key string, allowDollar data.EnumDollar, keyDollars int) (data.PatternList, error) {
+	return Patterns(i, key, allowDollar, keyDollars, false)
+}
+
+// Patterns converts a list of patterns.
+// A pattern can be a simple string or an object with the pattern and metadata:
+// {"pattern": "pkg/a", "reason": "..."}
+// With expiry the patterns can have an expiry date, ticket and owner, too:
+// {"pattern": "pkg/a", "expires": "2021-12-31", "ticket": "PROJ-123", "owner": "team-a"}
+func Patterns(i interface{}, key string, allowDollar data.EnumDollar, keyDollars int, withExpiry bool,
+) (data.PatternList, error) {
+	if i == nil {
+		return nil, nil
+	}
+
+	sl, ok := i.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected list for key '%s', got type: %T", key, i)
+	}
+
+	l := make([]data.Pattern, len(sl))
+	for i, v := range sl {
+		var err error
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			var s string
+			if s, err = String(v); err != nil {
+				return nil, fmt.Errorf("unable to convert list for key '%s' from JSON: %w", key, err)
+			}
+			if l[i], err = newPattern(s, key, allowDollar, keyDollars); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		meta := make(map[string]string, len(m))
+		for k, mv := range m {
+			if meta[k], err = String(mv); err != nil {
+				return nil, fmt.Errorf("unable to convert value '%s' for key '%s' from JSON: %w", k, key, err)
+			}
+			switch k {
+			case "pattern", "reason":
+			case "expires", "ticket", "owner":
+				if withExpiry {
+					break
+				}
+				fallthrough
+			default:
+				return nil, fmt.Errorf("unknown value '%s' for key '%s'", k, key)
+			}
+		}
+		if meta["pattern"] == "" {
+			return nil, fmt.Errorf("missing value 'pattern' for key '%s'", key)
+		}
+		if l[i], err = newPattern(meta["pattern"], key, allowDollar, keyDollars); err != nil {
+			return nil, err
+		}
+		if exp := meta["expires"]; exp != "" {
+			if l[i].Expires, err = time.Parse(dateLayout, exp); err != nil {
+				return nil, fmt.Errorf("unable to use expiry date %q of pattern `%s` of key '%s': %w",
+					exp, l[i].Pattern, key, err)
+			}
+		}
+		l[i].Ticket = meta["ticket"]
+		l[i].Owner = meta["owner"]
+		l[i].Reason = meta["reason"]
+	}
+	return data.PatternList(l), nil
+}
+
+func newPattern(s, key string, allowDollar data.EnumDollar, keyDollars int) (data.Pattern, error) {
+	re, _, dollarIdxs, err := data.RegexpForPattern(s, allowDollar, keyDollars)
+	if err != nil {
+		return data.Pattern{}, fmt.Errorf("unable to use pattern `%s` of key '%s': %w", s, key, err)
+	}
+	return data.Pattern{Pattern: s, Regexp: re, DollarIdxs: dollarIdxs}, nil
+}
+
+// UInt converts an unsigned integer value.
+func UInt(i interface{}) (uint, error) {
+	var f float64
+	var ok bool
+
+	if i == nil {
+		return 0, nil
+	}
+
+	if f, ok = i.(float64); !ok {
+		return 0, fmt.Errorf("expected positive integer value, got type: %T", i)
+	}
+
+	if f < 0.0 {
+		return 0, fmt.Errorf("expected positive integer value, got negative: %f", f)
+	}
+
+	if f > math.MaxUint32 {
+		return 0, fmt.Errorf("expected unsigned integer value, got too large: %f", f)
+	}
+
+	if f != math.Trunc(f) {
+		return 0, fmt.Errorf("expected unsigned integer value, got float: %f", f)
+	}
+
+	return uint(f), nil
+}
+
+// Bool converts a boolean value.
+func Bool(i interface{}) (bool, error) {
+	var b, ok bool
+
+	if i == nil {
+		return false, nil
+	}
+
+	if b, ok = i.(bool); !ok {
+		return false, fmt.Errorf("expected boolean value, got type: %T", i)
+	}
+
+	return b, nil
+}
+
+// String converts a string value.
+func String(i interface{}) (string, error) {
+	var s string
+	var ok bool
+
+	if i == nil {
+		return "", nil
+	}
+
+	if s, ok = i.(string); !ok {
+		return "", fmt.Errorf("expected string value, got type: %T", i)
+	}
+
+	return s, nil
+}
+
+// StringList converts a list of strings.
+func StringList(i interface{}) ([]string, error) {
+	if i == nil {
+		return nil, nil
+	}
+
+	il, ok := i.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected string list, got type: %T", i)
+	}
+
+	sl := make([]string, len(il))
+	for j, v := range il {
+		s, err := String(v)
+		if err != nil {
+			return nil, err
+		}
+		sl[j] = s
+	}
+	return sl, nil
+}
@@ -0,0 +1,105 @@
+package imports
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/flowdev/spaghetti-cutter/config/convert"
+	"github.com/flowdev/spaghetti-cutter/config/types"
+	"github.com/flowdev/spaghetti-cutter/data"
+)
+
+// Component is a named group of packages that is developed as a unit
+// (e.g. all packages of `pkg/orders/**`).
+type Component struct {
+	Name      string
+	Patterns  data.PatternList
+	MayImport []string // components that may be used by this component (nil for all)
+	Location  string   // file and line of the component in the configuration
+}
+
+// Components are the components in the order of the configuration.
+// A package belongs to the first component that matches it.
+type Components []Component
+
+// String implements Stringer and returns the components,
+// or "..." if there are none.
+func (cs Components) String() string {
+	if len(cs) <= 0 {
+		return "..."
+	}
+	var b strings.Builder
+	for i, c := range cs {
+		if i > 0 {
+			b.WriteString(" ; ")
+		}
+		b.WriteString("`")
+		b.WriteString(c.Name)
+		b.WriteString("`: ")
+		b.WriteString(c.Patterns.String())
+		b.WriteString(" -> ")
+		if c.MayImport == nil {
+			b.WriteString(types.All)
+		} else {
+			b.WriteString(types.Names(c.MayImport))
+		}
+	}
+	return b.String()
+}
+
+// ComponentsFromJSON converts the components of the given key.
+func ComponentsFromJSON(i interface{}, keyComponents string) (Components, error) {
+	if i == nil {
+		return nil, nil
+	}
+
+	sl, ok := i.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected list for key '%s', got type: %T", keyComponents, i)
+	}
+
+	cs := make(Components, len(sl))
+	names := make(map[string]bool, len(sl))
+	for i, v := range sl {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected map for component %d of key '%s', got type: %T", i+1, keyComponents, v)
+		}
+		for k := range m {
+			if k != "name" && k != "patterns" && k != "mayImport" {
+				return nil, fmt.Errorf("unknown value '%s' for component %d of key '%s'", k, i+1, keyComponents)
+			}
+		}
+		name, err := convert.String(m["name"])
+		if err != nil {
+			return nil, fmt.Errorf("unable to convert name of component %d of key '%s' from JSON: %w", i+1, keyComponents, err)
+		}
+		if name == "" {
+			return nil, fmt.Errorf("missing name for component %d of key '%s'", i+1, keyComponents)
+		}
+		if names[name] {
+			return nil, fmt.Errorf("duplicate component '%s' for key '%s'", name, keyComponents)
+		}
+		names[name] = true
+
+		c := Component{Name: name}
+		key := keyComponents + ": " + name
+		if c.Patterns, err = convert.PatternList(m["patterns"], key, data.EnumDollarNone, 0); err != nil {
+			return nil, err
+		}
+		if c.MayImport, err = convert.StringList(m["mayImport"]); err != nil {
+			return nil, fmt.Errorf("unable to convert 'mayImport' for key '%s' from JSON: %w", key, err)
+		}
+		cs[i] = c
+	}
+
+	for _, c := range cs {
+		for _, name := range c.MayImport {
+			if !names[name] {
+				return nil, fmt.Errorf("unknown component '%s' used by component '%s' of key '%s'",
+					name, c.Name, keyComponents)
+			}
+		}
+	}
+	return cs, nil
+}
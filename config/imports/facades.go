@@ -0,0 +1,37 @@
+package imports
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/flowdev/spaghetti-cutter/config/convert"
+	"github.com/flowdev/spaghetti-cutter/data"
+)
+
+// FacadeRoot returns the pattern of the root package of the module that is
+// protected by the given facade key (e.g. `pkg/$*` for `pkg/$*/**`).
+// Packages in the root package and below it are inside of the module.
+func FacadeRoot(key string) string {
+	return strings.TrimSuffix(key, "/**")
+}
+
+// FacadesFromJSON converts the facades map and ensures that the
+// modules are well defined: Each key has to end with `/**` and its root may
+// contain only variables (`$*` or `$**`) as wildcards.
+func FacadesFromJSON(i interface{}, keyFacades string) (*data.PatternMap, error) {
+	pm, err := convert.PatternMap(i, keyFacades, false)
+	if err != nil || pm == nil {
+		return pm, err
+	}
+	for key := range *pm {
+		root := FacadeRoot(key)
+		if root == key {
+			return nil, fmt.Errorf("the key %q of '%s' has to end with '/**'", key, keyFacades)
+		}
+		if strings.Contains(strings.ReplaceAll(strings.ReplaceAll(root, "$**", ""), "$*", ""), "*") {
+			return nil, fmt.Errorf("the key %q of '%s' may contain only '$*' or '$**' as wildcards before '/**'",
+				key, keyFacades)
+		}
+	}
+	return pm, nil
+}
@@ -0,0 +1,89 @@
+package imports
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/flowdev/spaghetti-cutter/config/convert"
+	"github.com/flowdev/spaghetti-cutter/data"
+)
+
+// FanLimit limits the number of internal packages that import the matching
+// packages (fan-in) and the number of internal and external packages that
+// they import (fan-out). A limit of 0 means no limit.
+type FanLimit struct {
+	Patterns          data.PatternList
+	MaxFanIn          uint
+	MaxFanOut         uint
+	MaxExternalFanOut uint
+}
+
+// FanLimits are the fan-in and fan-out limits in the order of the
+// configuration. The first limit that matches a package is used.
+type FanLimits []FanLimit
+
+// String implements Stringer and returns the limits,
+// or "..." if there are none.
+func (fls FanLimits) String() string {
+	if len(fls) <= 0 {
+		return "..."
+	}
+	var b strings.Builder
+	for i, fl := range fls {
+		if i > 0 {
+			b.WriteString(" ; ")
+		}
+		b.WriteString(fl.Patterns.String())
+		fmt.Fprintf(&b, ": in %d, out %d, external out %d", fl.MaxFanIn, fl.MaxFanOut, fl.MaxExternalFanOut)
+	}
+	return b.String()
+}
+
+// FanLimitsFromJSON converts the fan limits of the given key.
+func FanLimitsFromJSON(i interface{}, keyFanLimits string) (FanLimits, error) {
+	if i == nil {
+		return nil, nil
+	}
+
+	sl, ok := i.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected list for key '%s', got type: %T", keyFanLimits, i)
+	}
+
+	fls := make(FanLimits, len(sl))
+	for i, v := range sl {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected map for limit %d of key '%s', got type: %T", i+1, keyFanLimits, v)
+		}
+		fl := FanLimit{}
+		maxima := map[string]*uint{
+			"maxFanIn":          &fl.MaxFanIn,
+			"maxFanOut":         &fl.MaxFanOut,
+			"maxExternalFanOut": &fl.MaxExternalFanOut,
+		}
+		for k, v := range m {
+			if k == "patterns" {
+				continue
+			}
+			max, ok := maxima[k]
+			if !ok {
+				return nil, fmt.Errorf("unknown value '%s' for limit %d of key '%s'", k, i+1, keyFanLimits)
+			}
+			var err error
+			if *max, err = convert.UInt(v); err != nil {
+				return nil, fmt.Errorf("unable to convert '%s' of limit %d of key '%s' from JSON: %w",
+					k, i+1, keyFanLimits, err)
+			}
+		}
+		var err error
+		if fl.Patterns, err = convert.PatternList(m["patterns"], keyFanLimits, data.EnumDollarNone, 0); err != nil {
+			return nil, err
+		}
+		if len(fl.Patterns) == 0 {
+			return nil, fmt.Errorf("missing patterns for limit %d of key '%s'", i+1, keyFanLimits)
+		}
+		fls[i] = fl
+	}
+	return fls, nil
+}
@@ -0,0 +1,84 @@
+// Package imports contains the configuration of the rules for the imports
+// of packages: layers, components, facades, fan limits and tests.
+package imports
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/flowdev/spaghetti-cutter/config/convert"
+	"github.com/flowdev/spaghetti-cutter/data"
+)
+
+// Layer is a named group of packages.
+type Layer struct {
+	Name     string
+	Patterns data.PatternList
+	Location string // file and line of the layer in the configuration
+}
+
+// Layers are ordered from top to bottom. Packages of a layer may only import
+// packages of the same or lower layers.
+type Layers []Layer
+
+// String implements Stringer and returns the layers from top to bottom,
+// or "..." if there are none.
+func (ls Layers) String() string {
+	if len(ls) <= 0 {
+		return "..."
+	}
+	var b strings.Builder
+	for i, l := range ls {
+		if i > 0 {
+			b.WriteString(" > ")
+		}
+		b.WriteString("`")
+		b.WriteString(l.Name)
+		b.WriteString("`: ")
+		b.WriteString(l.Patterns.String())
+	}
+	return b.String()
+}
+
+// LayersFromJSON converts the layers of the given key.
+func LayersFromJSON(i interface{}, keyLayers string) (Layers, error) {
+	if i == nil {
+		return nil, nil
+	}
+
+	sl, ok := i.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected list for key '%s', got type: %T", keyLayers, i)
+	}
+
+	layers := make(Layers, len(sl))
+	names := make(map[string]bool, len(sl))
+	for i, v := range sl {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected map for layer %d of key '%s', got type: %T", i+1, keyLayers, v)
+		}
+		for k := range m {
+			if k != "name" && k != "patterns" {
+				return nil, fmt.Errorf("unknown value '%s' for layer %d of key '%s'", k, i+1, keyLayers)
+			}
+		}
+		name, err := convert.String(m["name"])
+		if err != nil {
+			return nil, fmt.Errorf("unable to convert name of layer %d of key '%s' from JSON: %w", i+1, keyLayers, err)
+		}
+		if name == "" {
+			return nil, fmt.Errorf("missing name for layer %d of key '%s'", i+1, keyLayers)
+		}
+		if names[name] {
+			return nil, fmt.Errorf("duplicate layer '%s' for key '%s'", name, keyLayers)
+		}
+		names[name] = true
+		pl, err := convert.PatternList(m["patterns"], keyLayers+": "+name, data.EnumDollarNone, 0)
+		if err != nil {
+			return nil, err
+		}
+		layers[i] = Layer{Name: name, Patterns: pl}
+	}
+	return layers, nil
+}
@@ -0,0 +1,63 @@
+package imports
+
+import (
+	"fmt"
+
+	"github.com/flowdev/spaghetti-cutter/config/convert"
+	"github.com/flowdev/spaghetti-cutter/config/types"
+)
+
+// Tests contains the import policy of test packages.
+// Without configuration test packages may import everything.
+type Tests struct {
+	// MayImport lists the types that black-box test packages (`_test`) may
+	// import besides the package under test (nil for all types).
+	MayImport []string
+	// InPackage makes the imports of in-package tests follow the rules of
+	// their package.
+	InPackage bool
+	Location  string // file and line of the tests in the configuration
+}
+
+// String implements Stringer and returns the policy of test packages.
+func (ts Tests) String() string {
+	mayImport := "*"
+	if ts.MayImport != nil {
+		mayImport = types.Names(ts.MayImport)
+	}
+	return fmt.Sprintf("-> %s inPackage: %t", mayImport, ts.InPackage)
+}
+
+// TestsFromJSON converts the policy of test packages of the given key.
+// The types that tests may import have to be known package types.
+func TestsFromJSON(i interface{}, keyTests string, tds types.TypeDefs) (Tests, error) {
+	ts := Tests{}
+	if i == nil {
+		return ts, nil
+	}
+
+	m, ok := i.(map[string]interface{})
+	if !ok {
+		return ts, fmt.Errorf("expected map for key '%s', got type: %T", keyTests, i)
+	}
+	for k := range m {
+		if k != "mayImport" && k != "inPackage" {
+			return ts, fmt.Errorf("unknown value '%s' for key '%s'", k, keyTests)
+		}
+	}
+
+	var err error
+	if ts.MayImport, err = convert.StringList(m["mayImport"]); err != nil {
+		return Tests{}, fmt.Errorf("unable to convert 'mayImport' for key '%s' from JSON: %w", keyTests, err)
+	}
+	if ts.InPackage, err = convert.Bool(m["inPackage"]); err != nil {
+		return Tests{}, fmt.Errorf("unable to convert 'inPackage' for key '%s' from JSON: %w", keyTests, err)
+	}
+
+	for _, t := range ts.MayImport {
+		if !tds.Known(t) {
+			return Tests{}, fmt.Errorf("unknown type '%s' used by key '%s'", t, keyTests)
+		}
+	}
+	return ts, nil
+}
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"github.com/flowdev/spaghetti-cutter/config/location"
+)
+
+// locate sets the location of all patterns in the configuration.
+func locate(l location.Locator, cfg *Config) {
+	for _, m := range cfg.patternMaps() {
+		l.Map(*m.pm, m.key)
+	}
+	l.Map(cfg.Facades, keyFacades)
+	for _, pl := range cfg.patternLists() {
+		l.List(*pl.pl, pl.key)
+	}
+	cfg.Tests.Location = l.Location(keyTests)
+	l.List(cfg.Concurrency.AllowedIn, keyConcurrency)
+	cfg.Concurrency.Location = l.Location(keyConcurrency)
+	l.List(cfg.Purity.Patterns, keyPurity)
+	l.List(cfg.Purity.AllowedVars, keyPurity)
+	cfg.Purity.Location = l.Location(keyPurity)
+	for i, td := range cfg.Types {
+		cfg.Types[i].Location = l.NamedLocation(keyTypes, td.Name)
+		for j, p := range td.Patterns {
+			td.Patterns[j].Location = l.NamedLocation(keyTypes, td.Name, p.Pattern)
+		}
+		for j, p := range td.ForbiddenImports {
+			td.ForbiddenImports[j].Location = l.NamedLocation(keyTypes, td.Name, "forbiddenImports", p.Pattern)
+		}
+		for j, p := range td.MayNotExposePackages {
+			td.MayNotExposePackages[j].Location = l.NamedLocation(keyTypes, td.Name, "mayNotExposePackages", p.Pattern)
+		}
+	}
+	for _, fl := range cfg.FanLimits {
+		l.List(fl.Patterns, keyFanLimits)
+	}
+	for i, c := range cfg.Components {
+		cfg.Components[i].Location = l.NamedLocation(keyComponents, c.Name)
+		for j, p := range c.Patterns {
+			c.Patterns[j].Location = l.NamedLocation(keyComponents, c.Name, p.Pattern)
+		}
+	}
+	for i, layer := range cfg.Layers {
+		cfg.Layers[i].Location = l.NamedLocation(keyLayers, layer.Name)
+		for j, p := range layer.Patterns {
+			layer.Patterns[j].Location = l.NamedLocation(keyLayers, layer.Name, p.Pattern)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+// Package location finds the locations of the patterns in the configuration
+// file.
+package location
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/flowdev/spaghetti-cutter/data"
+)
+
+// Locator finds the lines of patterns in the configuration file.
+// The HJSON parser doesn't keep any positions, so the raw text is searched
+// for the keys and the pattern.
+type Locator struct {
+	File     string
+	Text     string
+	Fallback string // location of patterns that aren't found (e.g. from a preset)
+}
+
+// Map sets the locations of all patterns of the map with the given key.
+func (l Locator) Map(pm *data.PatternMap, key string) {
+	if pm == nil {
+		return
+	}
+	for left, group := range *pm {
+		group.Left.Location = l.Location(key, left)
+		for i, right := range group.Right {
+			group.Right[i].Location = l.Location(key, left, right.Pattern)
+		}
+		(*pm)[left] = group
+	}
+}
+
+// List sets the locations of all patterns of the list with the given key.
+func (l Locator) List(pl data.PatternList, key string) {
+	for i, p := range pl {
+		pl[i].Location = l.Location(key, p.Pattern)
+	}
+}
+
+// Location returns the file and line of the last element of the path.
+// All elements except the last one are keys.
+// An empty string is returned if the path can't be found.
+func (l Locator) Location(path ...string) string {
+	return l.lineOf(l.find(0, len(l.Text), len(path)-1, path...))
+}
+
+// NamedLocation returns the file and line of the element of the list with the
+// given key that has got the given name (e.g.: {"name": "domain"}).
+// If values are given, the location of the last of them is returned instead.
+func (l Locator) NamedLocation(key, name string, values ...string) string {
+	start := l.find(0, len(l.Text), 1, key)
+	if start < 0 {
+		return l.Fallback
+	}
+	start += len(key)
+	end := start + valueEnd(l.Text[start:])
+	for {
+		start = l.find(start, end, 1, "name")
+		if start < 0 {
+			return l.Fallback
+		}
+		start += len("name")
+		rest := strings.TrimLeft(l.Text[start:], " \t\"':")
+		if indexOfToken(rest, name, false) == 0 {
+			start = len(l.Text) - len(rest)
+			if len(values) == 0 {
+				return l.lineOf(start)
+			}
+			return l.lineOf(l.find(start, end, 0, values...))
+		}
+	}
+}
+
+// find returns the index of the last element of the path in the text between
+// start and end or -1. The first n elements of the path are keys and every
+// following element is only searched in the value of the key before it.
+// So the same pattern in different sections is found under its own key.
+func (l Locator) find(start, end, n int, path ...string) int {
+	idx := -1
+	for i, s := range path {
+		idx = indexOfToken(l.Text[start:end], s, i < n)
+		if idx < 0 {
+			return -1
+		}
+		idx += start
+		start = idx + len(s)
+		if i < n {
+			end = start + valueEnd(l.Text[start:end])
+		}
+	}
+	return idx
+}
+
+func (l Locator) lineOf(idx int) string {
+	if idx < 0 {
+		return l.Fallback
+	}
+	return fmt.Sprintf("%s:%d", l.File, strings.Count(l.Text[:idx], "\n")+1)
+}
+
+// indexOfToken returns the index of the first complete (quoted or unquoted)
+// occurrence of tok in text or -1.
+// A key has to be followed by a colon and occurrences in line comments are
+// skipped.
+func indexOfToken(text, tok string, key bool) int {
+	const before = " \t\r\n{[,\"'"
+	const after = " \t\r\n:,]}\"'"
+
+	for start := 0; start < len(text); {
+		i := strings.Index(text[start:], tok)
+		if i < 0 {
+			return -1
+		}
+		i += start
+		end := i + len(tok)
+		start = i + 1
+		if i > 0 && !strings.ContainsRune(before, rune(text[i-1])) {
+			continue
+		}
+		if end < len(text) && !strings.ContainsRune(after, rune(text[end])) {
+			continue
+		}
+		if key && !strings.HasPrefix(strings.TrimLeft(text[end:], " \t\"'"), ":") {
+			continue
+		}
+		if commentStart(text[strings.LastIndexByte(text[:i], '\n')+1:i]) >= 0 {
+			continue
+		}
+		return i
+	}
+	return -1
+}
+
+// valueEnd returns the index in text after the value of a key.
+// The text has to start right after the key.
+// Objects and lists end at their closing bracket and all other values at the
+// end of the line.
+func valueEnd(text string) int {
+	i := strings.IndexByte(text, ':') + 1
+	for i < len(text) && strings.IndexByte(" \t", text[i]) >= 0 {
+		i++
+	}
+	if i >= len(text) || (text[i] != '{' && text[i] != '[') {
+		return lineEnd(text, i)
+	}
+	depth := 0
+	for ; i < len(text); i++ {
+		switch c := text[i]; {
+		case c == '"' || c == '\'':
+			i = stringEnd(text, i)
+		case c == '#' || strings.HasPrefix(text[i:], "//"):
+			i = lineEnd(text, i) - 1
+		case c == '{' || c == '[':
+			depth++
+		case c == '}' || c == ']':
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return len(text)
+}
+
+// commentStart returns the index of the line comment in line or -1.
+// Comment markers in quoted strings are ignored.
+func commentStart(line string) int {
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case c == '"' || c == '\'':
+			i = stringEnd(line, i)
+		case c == '#' || strings.HasPrefix(line[i:], "//"):
+			return i
+		}
+	}
+	return -1
+}
+
+// stringEnd returns the index of the closing quote of the string starting at
+// index i in text.
+func stringEnd(text string, i int) int {
+	q := text[i]
+	for i++; i < len(text) && text[i] != q && text[i] != '\n'; i++ {
+		if text[i] == '\\' {
+			i++
+		}
+	}
+	return i
+}
+
+func lineEnd(text string, i int) int {
+	if j := strings.IndexByte(text[i:], '\n'); j >= 0 {
+		return i + j
+	}
+	return len(text)
+}
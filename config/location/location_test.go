@@ -0,0 +1,114 @@
+package location_test
+
+import (
+	"testing"
+
+	"github.com/flowdev/spaghetti-cutter/config/location"
+)
+
+func TestLocation(t *testing.T) {
+	text := `{
+	"tool": [
+		"pkg/a", // trailing comment with "pkg/b"
+		{"pattern": "pkg/b", "reason": "see #12"}, "pkg/c"
+	]
+	# "god": ["pkg/d"]
+	"god": ["pkg/a"]
+	"deny": {
+		"cmd/*": ["pkg/c"]
+		"pkg/*": ["pkg/d", "pkg/c"]
+	}
+}`
+	l := location.Locator{File: "test.hjson", Text: text, Fallback: "preset"}
+	specs := []struct {
+		name             string
+		givenPath        []string
+		expectedLocation string
+	}{
+		{
+			name:             "trailing-comment",
+			givenPath:        []string{"tool", "pkg/a"},
+			expectedLocation: "test.hjson:3",
+		}, {
+			name:             "after-trailing-comment",
+			givenPath:        []string{"tool", "pkg/b"},
+			expectedLocation: "test.hjson:4",
+		}, {
+			name:             "after-comment-marker-in-string",
+			givenPath:        []string{"tool", "pkg/c"},
+			expectedLocation: "test.hjson:4",
+		}, {
+			name:             "duplicate-in-other-section",
+			givenPath:        []string{"god", "pkg/a"},
+			expectedLocation: "test.hjson:7",
+		}, {
+			name:             "missing-in-own-section",
+			givenPath:        []string{"god", "pkg/d"},
+			expectedLocation: "preset",
+		}, {
+			name:             "duplicate-in-other-group",
+			givenPath:        []string{"deny", "pkg/*", "pkg/c"},
+			expectedLocation: "test.hjson:10",
+		}, {
+			name:             "missing-in-own-group",
+			givenPath:        []string{"deny", "cmd/*", "pkg/d"},
+			expectedLocation: "preset",
+		},
+	}
+
+	for _, spec := range specs {
+		t.Run(spec.name, func(t *testing.T) {
+			actual := l.Location(spec.givenPath...)
+			if actual != spec.expectedLocation {
+				t.Errorf("expected location %q, actual %q", spec.expectedLocation, actual)
+			}
+		})
+	}
+}
+
+func TestNamedLocation(t *testing.T) {
+	text := `{
+	"layers": [
+		{"name": "a", "patterns": ["pkg/a"]}
+		{"name": "b", "patterns": ["pkg/a"]}
+	]
+	"components": [
+		{"name": "c", "patterns": ["pkg/a"]}
+	]
+}`
+	l := location.Locator{File: "test.hjson", Text: text, Fallback: "preset"}
+	specs := []struct {
+		name             string
+		givenKey         string
+		givenName        string
+		givenValues      []string
+		expectedLocation string
+	}{
+		{
+			name:             "name",
+			givenKey:         "layers",
+			givenName:        "b",
+			expectedLocation: "test.hjson:4",
+		}, {
+			name:             "duplicate-in-other-element",
+			givenKey:         "layers",
+			givenName:        "b",
+			givenValues:      []string{"pkg/a"},
+			expectedLocation: "test.hjson:4",
+		}, {
+			name:             "name-in-other-section",
+			givenKey:         "layers",
+			givenName:        "c",
+			expectedLocation: "preset",
+		},
+	}
+
+	for _, spec := range specs {
+		t.Run(spec.name, func(t *testing.T) {
+			actual := l.NamedLocation(spec.givenKey, spec.givenName, spec.givenValues...)
+			if actual != spec.expectedLocation {
+				t.Errorf("expected location %q, actual %q", spec.expectedLocation, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+// Package preset contains the built-in architecture presets and expands them
+// into the JSON configuration.
+package preset
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/flowdev/spaghetti-cutter/config/convert"
+)
+
+// Keys of the configuration that are used by the presets.
+const (
+	keyPreset            = "preset"
+	keyTool              = "tool"
+	keyDB                = "db"
+	keyGod               = "god"
+	keyNoGod             = "noGod"
+	keyLayers            = "layers"
+	keyAllowAdditionally = "allowAdditionally"
+	keyFacades           = "facades"
+)
+
+// presets contains the built-in architecture presets.
+// They are expanded into the configuration before the project's own keys are
+// added (see: mergeJSON).
+var presets = map[string]func() map[string]interface{}{
+	// web-api is the classic structure with tool, DB and god packages.
+	"web-api": func() map[string]interface{} {
+		return map[string]interface{}{
+			keyTool: list("x/*", "pkg/x/*", "internal/x/*"),
+			keyDB:   list("db/*", "pkg/db/*", "internal/db/*"),
+			keyGod:  list("main", "cmd/*"),
+		}
+	},
+	// hexagonal has got adapters that implement the ports of the application
+	// core (application services and domain).
+	"hexagonal": func() map[string]interface{} {
+		return map[string]interface{}{
+			keyTool: list("x/*", "pkg/x/*", "internal/x/*"),
+			keyGod:  list("main", "cmd/*"),
+			keyLayers: []interface{}{
+				layer("adapters", anywhere("adapter", "adapters")),
+				layer("application", anywhere("app", "application", "service")),
+				layer("ports", anywhere("port", "ports")),
+				layer("domain", anywhere("domain", "core")),
+			},
+		}
+	},
+	// clean follows the clean architecture from the outside to the inside.
+	"clean": func() map[string]interface{} {
+		return map[string]interface{}{
+			keyTool: list("x/*", "pkg/x/*", "internal/x/*"),
+			keyGod:  list("main", "cmd/*"),
+			keyLayers: []interface{}{
+				layer("frameworks", anywhere("framework", "frameworks", "infrastructure")),
+				layer("interface-adapters", anywhere("adapter", "adapters", "controller", "presenter", "gateway")),
+				layer("use-cases", anywhere("usecase", "usecases")),
+				layer("entities", anywhere("entity", "entities")),
+			},
+		}
+	},
+	// modular-monolith has got independent modules in `modules/<name>`
+	// that can only use the `api` packages of other modules.
+	"modular-monolith": func() map[string]interface{} {
+		return map[string]interface{}{
+			keyTool: list("x/*", "pkg/x/*", "internal/x/*"),
+			keyGod:  list("main", "cmd/*"),
+			keyAllowAdditionally: map[string]interface{}{
+				"modules/$*":    list("modules/$1/**", "modules/*/api"),
+				"modules/$*/**": list("modules/$1", "modules/$1/**", "modules/*/api"),
+			},
+			keyFacades: map[string]interface{}{
+				"modules/$*/**": list("modules/$1/api"),
+			},
+		}
+	},
+}
+
+// Names returns the names of all built-in presets.
+func Names() []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Expand replaces the preset key of the configuration with the rules of the
+// preset. The project's own keys extend the preset.
+// With `noGod` the god packages of the preset are dropped.
+func Expand(jsonCfg map[string]interface{}) (map[string]interface{}, error) {
+	name, err := convert.String(jsonCfg[keyPreset])
+	if err != nil {
+		return nil, fmt.Errorf("unable to convert preset from JSON: %w", err)
+	}
+	if name == "" {
+		return jsonCfg, nil
+	}
+	preset, ok := presets[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown preset '%s' (known presets are: %s)", name, strings.Join(Names(), ", "))
+	}
+	delete(jsonCfg, keyPreset)
+	presetCfg := preset()
+	if noGod, _ := convert.Bool(jsonCfg[keyNoGod]); noGod {
+		delete(presetCfg, keyGod)
+	}
+	return mergeJSON(presetCfg, jsonCfg).(map[string]interface{}), nil
+}
+
+// mergeJSON merges the project's configuration into the preset:
+// Maps are merged, lists are appended (elements with the same name replace
+// the preset's element in place) and other values replace the preset's value.
+func mergeJSON(preset, project interface{}) interface{} {
+	switch p := project.(type) {
+	case map[string]interface{}:
+		pm, ok := preset.(map[string]interface{})
+		if !ok {
+			return project
+		}
+		for k, v := range p {
+			if pv, ok := pm[k]; ok {
+				pm[k] = mergeJSON(pv, v)
+			} else {
+				pm[k] = v
+			}
+		}
+		return pm
+	case []interface{}:
+		pl, ok := preset.([]interface{})
+		if !ok {
+			return project
+		}
+		merged := make([]interface{}, 0, len(pl)+len(p))
+		for _, pv := range pl {
+			if i := indexOfName(p, nameOf(pv)); i >= 0 {
+				merged = append(merged, p[i])
+			} else {
+				merged = append(merged, pv)
+			}
+		}
+		for _, v := range p {
+			if indexOfName(pl, nameOf(v)) < 0 {
+				merged = append(merged, v)
+			}
+		}
+		return merged
+	}
+	return project
+}
+
+func nameOf(i interface{}) string {
+	if m, ok := i.(map[string]interface{}); ok {
+		if name, ok := m["name"].(string); ok {
+			return name
+		}
+	}
+	return ""
+}
+
+func indexOfName(l []interface{}, name string) int {
+	if name == "" {
+		return -1
+	}
+	for i, v := range l {
+		if nameOf(v) == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// anywhere returns patterns matching the given packages and their
+// sub-packages at the top level and under any other package.
+func anywhere(pkgs ...string) []interface{} {
+	l := make([]interface{}, 0, 4*len(pkgs))
+	for _, p := range pkgs {
+		l = append(l, p, p+"/**", "**/"+p, "**/"+p+"/**")
+	}
+	return l
+}
+
+func layer(name string, patterns []interface{}) map[string]interface{} {
+	return map[string]interface{}{"name": name, "patterns": patterns}
+}
+
+func list(ss ...string) []interface{} {
+	l := make([]interface{}, len(ss))
+	for i, s := range ss {
+		l[i] = s
+	}
+	return l
+}
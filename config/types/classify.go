@@ -0,0 +1,116 @@
+package types
+
+import (
+	"github.com/flowdev/spaghetti-cutter/data"
+	"github.com/flowdev/spaghetti-cutter/x/pkgs"
+)
+
+// Info contains the type of a package and the configuration that
+// determined it.
+type Info struct {
+	Type    pkgs.PkgType
+	Def     *TypeDef
+	Pattern *data.Pattern // nil for standard packages
+}
+
+// TypeOfPkg works like TypeOf but a package that doesn't match any type and
+// whose files are all generated is of type generated.
+func (tds TypeDefs) TypeOfPkg(pkg *pkgs.Package, relPkg, strictRelPkg string) Info {
+	ti := tds.TypeOf(relPkg, strictRelPkg)
+	if ti.Type != pkgs.PkgTypeStandard || !pkgs.IsGeneratedPackage(pkg) {
+		return ti
+	}
+	for i := range tds {
+		if tds[i].Name == Generated {
+			return Info{Type: pkgs.PkgTypeGenerated, Def: &tds[i]}
+		}
+	}
+	return ti
+}
+
+// TypeOf finds the type of a package as documented for WithBuiltins.
+func (tds TypeDefs) TypeOf(relPkg, strictRelPkg string) Info {
+	var std, found Info
+	subMatched := false
+
+	for i := range tds {
+		td := &tds[i]
+		switch {
+		case td.Name == Standard:
+			std = Info{Type: pkgs.PkgTypeStandard, Def: td}
+		case !IsBuiltin(td.Name):
+			if p, full := td.Patterns.MatchPackage(relPkg, strictRelPkg); full {
+				return Info{Type: pkgs.PkgType(td.Name), Def: td, Pattern: p}
+			}
+		default:
+			p, full := td.Patterns.MatchPackage(relPkg, strictRelPkg)
+			if full {
+				found = Info{Type: pkgs.PkgType(td.Name), Def: td, Pattern: p}
+			} else if p != nil && td.SubPackages && !subMatched {
+				found = Info{Type: pkgs.SubPackageType(pkgs.PkgType(td.Name)), Def: td, Pattern: p}
+			}
+			if p != nil && td.SubPackages {
+				subMatched = true
+			}
+		}
+	}
+	if found.Def == nil {
+		return std
+	}
+	return found
+}
+
+// IsSubPackage returns true if the package is a sub-package of a package of
+// its type definition.
+func (ti Info) IsSubPackage() bool {
+	return ti.Def.SubPackages && ti.Type != pkgs.PkgType(ti.Def.Name)
+}
+
+// Parent returns the name of the package that is fully matched by the pattern
+// that made the given package a sub-package.
+func (ti Info) Parent(relPkg, strictRelPkg string) string {
+	if strictRelPkg != "" {
+		if parent := ti.Pattern.Regexp.FindString(strictRelPkg); parent != "" {
+			return parent
+		}
+	}
+	return ti.Pattern.Regexp.FindString(relPkg)
+}
+
+// Because explains which type rule determined the type of the package.
+func (ti Info) Because() string {
+	p := ti.Pattern
+	if p == nil {
+		return ""
+	}
+	if IsBuiltin(ti.Def.Name) {
+		return data.Because(ti.Def.Name, "`"+p.Pattern+"`", p.Location, p.Reason)
+	}
+	return data.Because("types", "`"+ti.Def.Name+"`: `"+p.Pattern+"`", p.Location, p.Reason)
+}
+
+// Contains returns true if the type names contain the given type or All.
+func Contains(names []string, t pkgs.PkgType) bool {
+	for _, name := range names {
+		if name == All || pkgs.PkgType(name) == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Label returns the designation of packages of the given type used in
+// error messages.
+func Label(t pkgs.PkgType) string {
+	switch t {
+	case pkgs.PkgTypeStandard:
+		return "domain package"
+	case pkgs.PkgTypeDB:
+		return "DB package"
+	case pkgs.PkgTypeHalfDB:
+		return "DB sub-package"
+	case pkgs.PkgTypeHalfTool:
+		return "tool sub-package"
+	}
+	return string(t) + " package"
+}
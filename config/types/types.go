@@ -0,0 +1,229 @@
+// Package types contains the package types of the configuration and
+// classifies packages by them.
+package types
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/flowdev/spaghetti-cutter/config/convert"
+	"github.com/flowdev/spaghetti-cutter/data"
+)
+
+// Names of the built-in package types.
+const (
+	Standard  = "standard"
+	Tool      = "tool"
+	DB        = "db"
+	God       = "god"
+	Generated = "generated"
+	TestOnly  = "testOnly"
+)
+
+// All can be used in MayImport to allow imports of all types.
+const All = "*"
+
+// TypeDef defines a package type with the patterns of its packages and its
+// import policy.
+type TypeDef struct {
+	Name            string
+	Patterns        data.PatternList
+	SubPackages     bool     // sub-packages of matching packages get their own type: "<name> sub-package"
+	MayImport       []string // types that packages of this type may import (All for all)
+	MayBeImportedBy []string // types that may import packages of this type (empty for all)
+	// ForbiddenImports are the standard library and external packages that
+	// packages of this type may not import.
+	ForbiddenImports data.PatternList
+	// MayNotExpose are the types of the packages whose types may not be used
+	// in the exported API of packages of this type.
+	MayNotExpose []string
+	// MayNotExposePackages are the packages (of the project, standard library
+	// or external) whose types may not be used in the exported API of
+	// packages of this type.
+	MayNotExposePackages data.PatternList
+	Location             string // file and line of the type in the configuration
+}
+
+// TypeDefs are the package types defined in the configuration.
+type TypeDefs []TypeDef
+
+// String implements Stringer and returns the type definitions,
+// or "..." if there are none.
+func (tds TypeDefs) String() string {
+	if len(tds) <= 0 {
+		return "..."
+	}
+	var b strings.Builder
+	for i, td := range tds {
+		if i > 0 {
+			b.WriteString(" ; ")
+		}
+		b.WriteString("`")
+		b.WriteString(td.Name)
+		b.WriteString("`: ")
+		b.WriteString(td.Patterns.String())
+		b.WriteString(" -> ")
+		b.WriteString(Names(td.MayImport))
+		b.WriteString(" <- ")
+		b.WriteString(Names(td.MayBeImportedBy))
+		if len(td.ForbiddenImports) > 0 {
+			b.WriteString(" !> ")
+			b.WriteString(td.ForbiddenImports.String())
+		}
+		if len(td.MayNotExpose) > 0 || len(td.MayNotExposePackages) > 0 {
+			b.WriteString(" !api ")
+			b.WriteString(Names(td.MayNotExpose))
+			b.WriteString(" ")
+			b.WriteString(td.MayNotExposePackages.String())
+		}
+	}
+	return b.String()
+}
+
+// Names returns the given type names quoted for messages,
+// or "..." if there are none.
+func Names(names []string) string {
+	if len(names) <= 0 {
+		return "..."
+	}
+	return "`" + strings.Join(names, "`, `") + "`"
+}
+
+// WithBuiltins returns the package types defined in the configuration followed
+// by the built-in types with the given patterns. A package has got the type of
+// the first configured type that matches it.
+// The built-in types are matched like this (later matches win):
+// god, generated, db, tool (the tool sub-package type only if the package
+// isn't a DB (sub-)package) and testOnly. Packages that don't match any type
+// are of type standard.
+// The import policy of a built-in type can be changed in the configuration by
+// defining a type with the same name and without patterns.
+func (tds TypeDefs) WithBuiltins(god, generated, db, tool, testOnly data.PatternList) TypeDefs {
+	builtins := TypeDefs{
+		{Name: God, Patterns: god, MayImport: []string{All}},
+		{Name: Generated, Patterns: generated, MayImport: []string{All}},
+		{Name: DB, Patterns: db, SubPackages: true, MayImport: []string{Tool, Generated}},
+		{Name: Tool, Patterns: tool, SubPackages: true},
+		{Name: TestOnly, Patterns: testOnly, MayImport: []string{Tool, DB, Generated, TestOnly}},
+		{Name: Standard, MayImport: []string{Tool, DB, Generated}},
+	}
+
+	all := make(TypeDefs, 0, len(tds)+len(builtins))
+	for _, td := range tds {
+		if !IsBuiltin(td.Name) {
+			all = append(all, td)
+		}
+	}
+	for _, b := range builtins {
+		for _, td := range tds {
+			if td.Name == b.Name {
+				b.MayImport, b.MayBeImportedBy, b.Location = td.MayImport, td.MayBeImportedBy, td.Location
+				b.ForbiddenImports = td.ForbiddenImports
+				b.MayNotExpose, b.MayNotExposePackages = td.MayNotExpose, td.MayNotExposePackages
+			}
+		}
+		all = append(all, b)
+	}
+	return all
+}
+
+// IsBuiltin returns true if name is the name of a built-in package type.
+func IsBuiltin(name string) bool {
+	switch name {
+	case Standard, Tool, DB, God, Generated, TestOnly:
+		return true
+	}
+	return false
+}
+
+// Known returns true if name can be used in the import policies of the
+// package types: the name of a package type (including the built-in ones and
+// their sub-package types) or All.
+func (tds TypeDefs) Known(name string) bool {
+	for _, td := range tds {
+		if td.Name == name {
+			return true
+		}
+	}
+	return IsBuiltin(name) || name == All || name == Tool+" sub-package" || name == DB+" sub-package"
+}
+
+// FromJSON converts the package types of the given key.
+func FromJSON(i interface{}, keyTypes string) (TypeDefs, error) {
+	if i == nil {
+		return nil, nil
+	}
+
+	sl, ok := i.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected list for key '%s', got type: %T", keyTypes, i)
+	}
+
+	tds := make(TypeDefs, len(sl))
+	names := make(map[string]bool, len(sl))
+	for i, v := range sl {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected map for type %d of key '%s', got type: %T", i+1, keyTypes, v)
+		}
+		for k := range m {
+			switch k {
+			case "name", "patterns", "mayImport", "mayBeImportedBy", "forbiddenImports",
+				"mayNotExpose", "mayNotExposePackages":
+			default:
+				return nil, fmt.Errorf("unknown value '%s' for type %d of key '%s'", k, i+1, keyTypes)
+			}
+		}
+		name, err := convert.String(m["name"])
+		if err != nil {
+			return nil, fmt.Errorf("unable to convert name of type %d of key '%s' from JSON: %w", i+1, keyTypes, err)
+		}
+		if name == "" || name == All {
+			return nil, fmt.Errorf("missing or illegal name %q for type %d of key '%s'", name, i+1, keyTypes)
+		}
+		if names[name] {
+			return nil, fmt.Errorf("duplicate type '%s' for key '%s'", name, keyTypes)
+		}
+		names[name] = true
+
+		td := TypeDef{Name: name}
+		key := keyTypes + ": " + name
+		if td.Patterns, err = convert.PatternList(m["patterns"], key, data.EnumDollarNone, 0); err != nil {
+			return nil, err
+		}
+		if IsBuiltin(name) && len(td.Patterns) > 0 {
+			return nil, fmt.Errorf("the built-in type '%s' of key '%s' can't have patterns (please use key '%s')",
+				name, keyTypes, name)
+		}
+		if td.MayImport, err = convert.StringList(m["mayImport"]); err != nil {
+			return nil, fmt.Errorf("unable to convert 'mayImport' for key '%s' from JSON: %w", key, err)
+		}
+		if td.MayBeImportedBy, err = convert.StringList(m["mayBeImportedBy"]); err != nil {
+			return nil, fmt.Errorf("unable to convert 'mayBeImportedBy' for key '%s' from JSON: %w", key, err)
+		}
+		td.ForbiddenImports, err = convert.PatternList(m["forbiddenImports"], key+" forbiddenImports",
+			data.EnumDollarNone, 0)
+		if err != nil {
+			return nil, err
+		}
+		if td.MayNotExpose, err = convert.StringList(m["mayNotExpose"]); err != nil {
+			return nil, fmt.Errorf("unable to convert 'mayNotExpose' for key '%s' from JSON: %w", key, err)
+		}
+		td.MayNotExposePackages, err = convert.PatternList(m["mayNotExposePackages"],
+			key+" mayNotExposePackages", data.EnumDollarNone, 0)
+		if err != nil {
+			return nil, err
+		}
+		tds[i] = td
+	}
+
+	for _, td := range tds {
+		all := append(append(append([]string{}, td.MayImport...), td.MayBeImportedBy...), td.MayNotExpose...)
+		for _, t := range all {
+			if !tds.Known(t) {
+				return nil, fmt.Errorf("unknown type '%s' used by type '%s' of key '%s'", t, td.Name, keyTypes)
+			}
+		}
+	}
+	return tds, nil
+}
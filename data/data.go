@@ -3,6 +3,7 @@ package data
 import (
 	"fmt"
 	"regexp"
+	"time"
 )
 
 // PkgType can be one of: Standard, Tool, DB or God
@@ -43,10 +44,22 @@ const (
 
 // Pattern combines the original pattern string with a compiled regular
 // expression ready for efficient evaluation.
+// Optional metadata documents temporary exceptions.
 type Pattern struct {
 	Pattern    string
 	Regexp     *regexp.Regexp
 	DollarIdxs []int
+	Expires    time.Time // zero if the pattern never expires
+	Ticket     string
+	Owner      string
+	Reason     string // why the pattern is configured
+	Location   string // file and line of the pattern in the configuration
+}
+
+// Expired returns true if the pattern has got an expiry date and the given
+// time is after that day.
+func (p Pattern) Expired(now time.Time) bool {
+	return !p.Expires.IsZero() && !now.Before(p.Expires.AddDate(0, 0, 1))
 }
 
 // RegexpForPattern converts the given pattern including wildcards and variables
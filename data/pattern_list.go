@@ -68,6 +68,22 @@ func (pl PatternList) MatchStringIndex(s string, dollars []string) (idx int, ful
 	}
 	return idx, false
 }
+
+// MatchPackage returns the pattern in the list that matches the given package
+// (or nil) and an indicator if it was a full match.
+// The strict package name is tried first (if given).
+func (pl PatternList) MatchPackage(pkg, strictPkg string) (p *Pattern, full bool) {
+	if strictPkg != "" {
+		if i, full := pl.MatchStringIndex(strictPkg, nil); i >= 0 {
+			return &pl[i], full
+		}
+	}
+	if i, full := pl.MatchStringIndex(pkg, nil); i >= 0 {
+		return &pl[i], full
+	}
+	return nil, false
+}
+
 func matchDollars(given, found []string, idxs []int) bool {
 	for i, f := range found {
 		if f != given[idxs[i]] {
@@ -41,6 +41,60 @@ func TestPatternListString(t *testing.T) {
 	}
 }
 
+func TestPatternListMatchPackage(t *testing.T) {
+	specs := []struct {
+		name            string
+		givenPkg        string
+		givenStrictPkg  string
+		expectedPattern string
+		expectedFull    bool
+	}{
+		{
+			name:            "full-match",
+			givenPkg:        "a/b",
+			expectedPattern: "a/*",
+			expectedFull:    true,
+		}, {
+			name:            "half-match",
+			givenPkg:        "a/b/c",
+			expectedPattern: "a/*",
+		}, {
+			name:            "strict-first",
+			givenPkg:        "main",
+			givenStrictPkg:  "cmd/x",
+			expectedPattern: "cmd/*",
+			expectedFull:    true,
+		}, {
+			name:            "strict-no-match",
+			givenPkg:        "main",
+			givenStrictPkg:  "b",
+			expectedPattern: "main",
+			expectedFull:    true,
+		}, {
+			name:     "no-match",
+			givenPkg: "b",
+		},
+	}
+
+	pl, err := data.NewSimplePatternList([]string{"a/*", "cmd/*", "main"}, "test")
+	if err != nil {
+		t.Fatalf("got unexpected error: %v", err)
+	}
+	for _, spec := range specs {
+		t.Run(spec.name, func(t *testing.T) {
+			p, full := pl.MatchPackage(spec.givenPkg, spec.givenStrictPkg)
+			actualPattern := ""
+			if p != nil {
+				actualPattern = p.Pattern
+			}
+			if actualPattern != spec.expectedPattern || full != spec.expectedFull {
+				t.Errorf("expected pattern %q (full: %t), actual %q (full: %t)",
+					spec.expectedPattern, spec.expectedFull, actualPattern, full)
+			}
+		})
+	}
+}
+
 func TestPatternListMatchString(t *testing.T) {
 	specs := []struct {
 		name                string
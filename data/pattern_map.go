@@ -1,8 +1,10 @@
 package data
 
 import (
+	"fmt"
 	"sort"
 	"strings"
+	"time"
 )
 
 type PatternGroup struct {
@@ -20,13 +22,8 @@ func (pm *PatternMap) String() string {
 	if pm == nil || len(*pm) <= 0 {
 		return "....."
 	}
-	keys := make([]string, 0, len(*pm))
-	for k := range *pm {
-		keys = append(keys, k)
-	}
-	sort.Strings(keys)
 	var b strings.Builder
-	for _, left := range keys {
+	for _, left := range pm.sortedKeys() {
 		b.WriteString("`")
 		b.WriteString(left)
 		b.WriteString("`")
@@ -42,27 +39,75 @@ func (pm *PatternMap) String() string {
 // The strict versions are checked first
 // (1. strictKey+strictValue, 2. strictKey+value, 3. key+strictValue, 4. key+value).
 func (pm *PatternMap) HasKeyValue(key, strictKey, value, strictValue string) (hasKey, hasValue bool) {
+	group, match := pm.MatchKeyValue(key, strictKey, value, strictValue)
+	return group != nil, match != nil
+}
+
+// MatchKeyValue works like HasKeyValue but returns the group with the
+// matching key (or nil) and the matching value pattern (or nil).
+// If no value matches, the first group (ordered by key) with a matching key
+// is returned.
+func (pm *PatternMap) MatchKeyValue(key, strictKey, value, strictValue string) (group *PatternGroup, match *Pattern) {
 	if pm == nil {
-		return false, false
+		return nil, nil
 	}
 
 	for _, k := range []string{strictKey, key} {
 		if k == "" {
 			continue
 		}
-		for _, group := range *pm {
-			if m := group.Left.Regexp.FindStringSubmatch(k); len(m) > 0 {
+		for _, left := range pm.sortedKeys() {
+			g := (*pm)[left]
+			if m := g.Left.Regexp.FindStringSubmatch(k); len(m) > 0 {
 				dollars := m[1:]
 
-				if _, full := group.Right.MatchString(strictValue, dollars); strictValue != "" && full {
-					return true, true
+				if i, full := g.Right.MatchStringIndex(strictValue, dollars); strictValue != "" && full {
+					return &g, &g.Right[i]
 				}
-				if _, full := group.Right.MatchString(value, dollars); value != "" && full {
-					return true, true
+				if i, full := g.Right.MatchStringIndex(value, dollars); value != "" && full {
+					return &g, &g.Right[i]
 				}
-				hasKey = true
+				if group == nil {
+					group = &g
+				}
+			}
+		}
+	}
+	return group, nil
+}
+
+func (pm *PatternMap) sortedKeys() []string {
+	keys := make([]string, 0, len(*pm))
+	for k := range *pm {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Expired returns a violation for every value pattern that is expired at the
+// given time. The key is the configuration key of the map.
+func (pm *PatternMap) Expired(key string, now time.Time) []error {
+	if pm == nil {
+		return nil
+	}
+
+	var errs []error
+	for _, left := range pm.sortedKeys() {
+		for _, right := range (*pm)[left].Right {
+			if !right.Expired(now) {
+				continue
+			}
+			msg := fmt.Sprintf("the %s entry `%s`: `%s` expired on %s",
+				key, left, right.Pattern, right.Expires.Format("2006-01-02"))
+			if right.Ticket != "" {
+				msg += " (ticket: " + right.Ticket + ")"
+			}
+			if right.Owner != "" {
+				msg += " (owner: " + right.Owner + ")"
 			}
+			errs = append(errs, &Violation{Check: "expired-" + key, Pkg: left, Target: right.Pattern, Msg: msg})
 		}
 	}
-	return hasKey, false
+	return errs
 }
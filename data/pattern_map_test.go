@@ -2,6 +2,7 @@ package data_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/flowdev/spaghetti-cutter/config"
 )
@@ -156,3 +157,61 @@ func TestPatternMapHasKeyValue(t *testing.T) {
 		})
 	}
 }
+
+func TestPatternMapExpired(t *testing.T) {
+	specs := []struct {
+		name           string
+		givenJSON      string
+		givenNow       time.Time
+		expectedErrors []string
+	}{
+		{
+			name:           "no-expiry",
+			givenJSON:      `"a": ["b"]`,
+			givenNow:       time.Date(2021, 12, 31, 12, 0, 0, 0, time.UTC),
+			expectedErrors: nil,
+		}, {
+			name:           "not-expired-yet",
+			givenJSON:      `"a": [{"pattern": "b", "expires": "2021-12-31"}]`,
+			givenNow:       time.Date(2021, 12, 31, 23, 59, 0, 0, time.UTC),
+			expectedErrors: nil,
+		}, {
+			name:      "expired",
+			givenJSON: `"a": [{"pattern": "b", "expires": "2021-12-31"}]`,
+			givenNow:  time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+			expectedErrors: []string{
+				"the allowOnlyIn entry `a`: `b` expired on 2021-12-31",
+			},
+		}, {
+			name: "expired-with-ticket-and-owner",
+			givenJSON: `"x": [{"pattern": "y", "expires": "2021-06-30"}],
+				"a": ["c", {"pattern": "b", "expires": "2021-12-31", "ticket": "PROJ-123", "owner": "team-a"}]`,
+			givenNow: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+			expectedErrors: []string{
+				"the allowOnlyIn entry `a`: `b` expired on 2021-12-31 (ticket: PROJ-123) (owner: team-a)",
+				"the allowOnlyIn entry `x`: `y` expired on 2021-06-30",
+			},
+		},
+	}
+
+	for _, spec := range specs {
+		t.Run(spec.name, func(t *testing.T) {
+			cfgBytes := []byte(`{ "allowOnlyIn": { ` + spec.givenJSON + ` } }`)
+			cfg, err := config.Parse(cfgBytes, spec.name)
+			if err != nil {
+				t.Fatalf("got unexpected error: %v", err)
+			}
+
+			actualErrors := cfg.AllowOnlyIn.Expired("allowOnlyIn", spec.givenNow)
+
+			if len(actualErrors) != len(spec.expectedErrors) {
+				t.Fatalf("expected %d errors, got %d: %q", len(spec.expectedErrors), len(actualErrors), actualErrors)
+			}
+			for i, err := range actualErrors {
+				if err.Error() != spec.expectedErrors[i] {
+					t.Errorf("expected error %q, got %q", spec.expectedErrors[i], err.Error())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package data
+
+// Violation is an error found by one of the checks.
+// Its fingerprint doesn't depend on positions or sizes, so it stays stable
+// while the code changes.
+type Violation struct {
+	Check  string // name of the check (e.g. "deps" or "size")
+	Pkg    string // unique relative name of the offending package
+	Target string // the offending thing (e.g. an imported package), if any
+	Msg    string // human readable message
+	Size   uint   // measured size that may not grow (e.g. of the package), if any
+}
+
+// Error implements the error interface and returns the message.
+func (v *Violation) Error() string {
+	return v.Msg
+}
+
+// Fingerprint returns a stable identification of the violation.
+func (v *Violation) Fingerprint() string {
+	fp := v.Check + ": " + v.Pkg
+	if v.Target != "" {
+		fp += " -> " + v.Target
+	}
+	return fp
+}
+
+// Because explains which rule of the configuration caused a violation:
+// ` (because of <key> <rule> in <location>: <reason>)`
+// The rule, location and reason are optional.
+func Because(key, rule, location, reason string) string {
+	s := " (because of " + key
+	if rule != "" {
+		s += " " + rule
+	}
+	if location != "" {
+		s += " in " + location
+	}
+	if reason != "" {
+		s += ": " + reason
+	}
+	return s + ")"
+}
@@ -0,0 +1,78 @@
+package data_test
+
+import (
+	"testing"
+
+	"github.com/flowdev/spaghetti-cutter/data"
+)
+
+func TestViolationFingerprint(t *testing.T) {
+	specs := []struct {
+		name                string
+		givenViolation      data.Violation
+		expectedFingerprint string
+	}{
+		{
+			name:                "with-target",
+			givenViolation:      data.Violation{Check: "deps", Pkg: "a", Target: "b", Msg: "a imports b"},
+			expectedFingerprint: "deps: a -> b",
+		}, {
+			name:                "without-target",
+			givenViolation:      data.Violation{Check: "size", Pkg: "a", Msg: "a is 123 big"},
+			expectedFingerprint: "size: a",
+		},
+	}
+
+	for _, spec := range specs {
+		t.Run(spec.name, func(t *testing.T) {
+			actualFingerprint := spec.givenViolation.Fingerprint()
+			if actualFingerprint != spec.expectedFingerprint {
+				t.Errorf("expected fingerprint %q, actual %q", spec.expectedFingerprint, actualFingerprint)
+			}
+		})
+	}
+}
+
+func TestBecause(t *testing.T) {
+	specs := []struct {
+		name            string
+		givenKey        string
+		givenRule       string
+		givenLocation   string
+		givenReason     string
+		expectedBecause string
+	}{
+		{
+			name:            "rule-only",
+			givenKey:        "tool",
+			givenRule:       "`x/*`",
+			expectedBecause: " (because of tool `x/*`)",
+		}, {
+			name:            "key-only",
+			givenKey:        "purity",
+			expectedBecause: " (because of purity)",
+		}, {
+			name:            "all",
+			givenKey:        "deny",
+			givenRule:       "`a/*`: `b`",
+			givenLocation:   ".spaghetti-cutter.hjson:3",
+			givenReason:     "keep them apart",
+			expectedBecause: " (because of deny `a/*`: `b` in .spaghetti-cutter.hjson:3: keep them apart)",
+		}, {
+			name:            "no-location",
+			givenKey:        "allowOnlyIn",
+			givenRule:       "`github.com/lib/pq`",
+			givenReason:     "only the DB layer talks to postgres",
+			expectedBecause: " (because of allowOnlyIn `github.com/lib/pq`: only the DB layer talks to postgres)",
+		},
+	}
+
+	for _, spec := range specs {
+		t.Run(spec.name, func(t *testing.T) {
+			actualBecause := data.Because(spec.givenKey, spec.givenRule, spec.givenLocation, spec.givenReason)
+			if actualBecause != spec.expectedBecause {
+				t.Errorf("expected %q, actual %q", spec.expectedBecause, actualBecause)
+			}
+		})
+	}
+}
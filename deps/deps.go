@@ -6,155 +6,178 @@ import (
 
 	"github.com/flowdev/spaghetti-cutter/config"
 	"github.com/flowdev/spaghetti-cutter/data"
+	"github.com/flowdev/spaghetti-cutter/deps/rules"
 	"github.com/flowdev/spaghetti-cutter/x/pkgs"
 )
 
 // Check checks the dependencies of the given package and reports offending
 // imports.
-func Check(pkg *pkgs.Package, rootPkg string, cfg config.Config) []error {
+// modules maps the package paths to their module paths (see: rules.CheckExternal).
+func Check(pkg *pkgs.Package, rootPkg string, modules map[string]string, cfg config.Config) []error {
 	relPkg, strictRelPkg := pkgs.RelativePackageName(pkg, rootPkg)
-	checkSpecial := checkStandard
+	unqPkg := pkgs.UniquePackageName(relPkg, strictRelPkg)
+	imps, supps, errs := fileImports(pkg, unqPkg, false)
+	defer reportUnusedSuppressions(supps)
+
+	errs = append(errs, checkPkg(pkg, pkg.Imports, imps, false, relPkg, strictRelPkg, rootPkg, cfg)...)
+	errs = append(errs, report("transitive", unqPkg, imps,
+		rules.CheckTransitive(pkg, relPkg, strictRelPkg, rootPkg, cfg))...)
+	errs = append(errs, report("external", unqPkg, imps,
+		rules.CheckExternal(pkg, unqPkg, rootPkg, modules, cfg))...)
+	return errs
+}
+
+// Type returns the type of the given package according to the configuration.
+func Type(pkg *pkgs.Package, rootPkg string, cfg config.Config) pkgs.PkgType {
+	relPkg, strictRelPkg := pkgs.RelativePackageName(pkg, rootPkg)
+	return cfg.AllTypeDefs().TypeOfPkg(pkg, relPkg, strictRelPkg).Type
+}
 
-	var fullmatch, matchDB bool
-	if _, fullmatch = isPackageInList(cfg.God, nil, relPkg, strictRelPkg); fullmatch {
-		checkSpecial = checkGod
+// Layer returns the name of the layer of the given package or an empty
+// string if the package isn't part of any layer.
+func Layer(pkg *pkgs.Package, rootPkg string, cfg config.Config) string {
+	relPkg, strictRelPkg := pkgs.RelativePackageName(pkg, rootPkg)
+	if i := rules.LayerOf(relPkg, strictRelPkg, cfg); i >= 0 {
+		return cfg.Layers[i].Name
 	}
-	if matchDB, fullmatch = isPackageInList(cfg.DB, nil, relPkg, strictRelPkg); matchDB {
-		if fullmatch {
-			checkSpecial = checkDB
-		} else {
-			checkSpecial = checkHalfDB
-		}
+	return ""
+}
+
+// CheckTests checks the imports of the in-package tests of the given package
+// with the rules of the package itself (if configured with
+// `tests: {inPackage: true}`).
+// Only imports that the package itself doesn't have are checked.
+func CheckTests(pkgInfo *pkgs.PackageInfo, rootPkg string, cfg config.Config) []error {
+	if !cfg.Tests.InPackage || pkgInfo.TestPkg == nil {
+		return nil
 	}
-	if matchTool, fullmatch := isPackageInList(cfg.Tool, nil, relPkg, strictRelPkg); matchTool {
-		if fullmatch {
-			checkSpecial = checkTool
-		} else if !matchDB {
-			checkSpecial = checkHalfTool
+	imports := make(map[string]*pkgs.Package)
+	for path, p := range pkgInfo.TestPkg.Imports {
+		if _, ok := pkgInfo.Pkg.Imports[path]; !ok {
+			imports[path] = p
 		}
 	}
-
-	errs := checkPkg(pkg, relPkg, strictRelPkg, rootPkg, cfg, checkSpecial)
-	return errs
+	relPkg, strictRelPkg := pkgs.RelativePackageName(pkgInfo.Pkg, rootPkg)
+	imps, supps, errs := fileImports(pkgInfo.TestPkg, pkgs.UniquePackageName(relPkg, strictRelPkg), true)
+	defer reportUnusedSuppressions(supps)
+	return append(errs, checkPkg(pkgInfo.TestPkg, imports, imps, true, relPkg, strictRelPkg, rootPkg, cfg)...)
 }
 
+// checkPkg checks the given imports of the package.
+// imps are the imports per file of the package (see: fileImports).
+// inTest is true if the imports are used only by the in-package tests.
 func checkPkg(
 	pkg *pkgs.Package,
+	imports map[string]*pkgs.Package,
+	imps map[string][]fileImport,
+	inTest bool,
 	relPkg, strictRelPkg, rootPkg string,
 	cfg config.Config,
-	checkSpecial func(string, string, string, string, config.Config) error,
 ) (errs []error) {
 	unqPkg := pkgs.UniquePackageName(relPkg, strictRelPkg)
+	tds := cfg.AllTypeDefs()
+	pkgT := tds.TypeOfPkg(pkg, relPkg, strictRelPkg)
+	splitGenerated := cfg.GeneratedFiles.Deps != config.GeneratedNormal
+
+	addViolation := func(check, path, unqImp string, err error, generated bool) {
+		v := newViolation(check, unqPkg, unqImp, err, generated)
+		if !suppress(imps[path], splitGenerated, v) {
+			errs = append(errs, v)
+		}
+	}
 
-	for _, p := range pkg.Imports {
-		relImp, strictRelImp := "", ""
-		internal := false
-
-		if strings.HasPrefix(p.PkgPath, rootPkg) {
-			relImp, strictRelImp = pkgs.RelativePackageName(p, rootPkg)
-			internal = true
-		} else {
-			strictRelImp = p.PkgPath
+	for path, p := range imports {
+		generated := splitGenerated && onlyGenerated(imps[path])
+		if generated && cfg.GeneratedFiles.Deps == config.GeneratedIgnore {
+			continue
 		}
+		relImp, strictRelImp := pkgs.RelativeImportName(p, rootPkg)
+		internal := strings.HasPrefix(p.PkgPath, rootPkg)
 		unqImp := pkgs.UniquePackageName(relImp, strictRelImp)
 
-		if hasKey, hasValue := cfg.AllowOnlyIn.HasKeyValue(relImp, strictRelImp, relPkg, strictRelPkg); hasKey {
-			if !hasValue {
-				errs = append(errs, fmt.Errorf(
-					"package '%s' isn't allowed to import package '%s' (because of allowOnlyIn)",
-					unqPkg, unqImp))
+		if group, deny := cfg.Deny.MatchKeyValue(relPkg, strictRelPkg, relImp, strictRelImp); deny != nil {
+			reason := deny.Reason
+			if reason == "" {
+				reason = group.Left.Reason
+			}
+			// deny can't be overruled, not even by a suppression
+			errs = append(errs, newViolation("deps", unqPkg, unqImp, fmt.Errorf(
+				"package '%s' isn't allowed to import package '%s'%s",
+				unqPkg, unqImp, data.Because("deny", "`"+group.Left.Pattern+"`: `"+deny.Pattern+"`", deny.Location, reason),
+			), generated))
+			continue
+		}
+
+		if !internal {
+			if err := rules.CheckForbiddenImport(pkgT, relPkg, strictRelPkg, relImp, strictRelImp); err != nil {
+				addViolation("deps", path, unqImp, err, generated)
+				continue
+			}
+		}
+
+		if group, allowed := cfg.AllowOnlyIn.MatchKeyValue(relImp, strictRelImp, relPkg, strictRelPkg); group != nil {
+			if allowed == nil {
+				addViolation("deps", path, unqImp, fmt.Errorf(
+					"package '%s' isn't allowed to import package '%s'%s",
+					unqPkg, unqImp, data.Because("allowOnlyIn", "`"+group.Left.Pattern+"`", group.Left.Location, group.Left.Reason),
+				), generated)
 			}
 			continue
 		}
 
 		if internal {
+			if err := rules.CheckFacade(relPkg, strictRelPkg, relImp, strictRelImp, cfg); err != nil {
+				addViolation("facades", path, unqImp, err, generated)
+				continue
+			}
+
 			// check in allow first:
 			if hasKey, hasValue := cfg.AllowAdditionally.HasKeyValue(relPkg, strictRelPkg, relImp, strictRelImp); hasKey && hasValue {
 				continue // this import is fine
 			}
 
-			if err := checkSpecial(relPkg, strictRelPkg, relImp, strictRelImp, cfg); err != nil {
-				errs = append(errs, err)
+			if layered, err := rules.CheckLayers(relPkg, strictRelPkg, relImp, strictRelImp, cfg); layered {
+				if err != nil {
+					addViolation("layers", path, unqImp, err, generated)
+				}
+				continue
+			}
+
+			impT := tds.TypeOfPkg(p, relImp, strictRelImp)
+			if err := rules.CheckTypes(pkgT, impT, inTest, relPkg, strictRelPkg, relImp, strictRelImp, cfg.Tests); err != nil {
+				addViolation("deps", path, unqImp, err, generated)
 			}
 		}
 	}
 	return errs
 }
 
-func checkTool(relPkg, strictRelPkg, relImp, strictRelImp string, cfg config.Config) error {
-	if isTestPackage(relPkg, strictRelPkg) {
-		return nil
-	}
-	return fmt.Errorf("tool package '%s' isn't allowed to import package '%s'",
-		pkgs.UniquePackageName(relPkg, strictRelPkg),
-		pkgs.UniquePackageName(relImp, strictRelImp))
-}
-
-func checkHalfTool(relPkg, strictRelPkg, relImp, strictRelImp string, cfg config.Config) error {
-	if isTestPackage(relPkg, strictRelPkg) {
-		return nil
-	}
-	return fmt.Errorf("tool sub-package '%s' isn't allowed to import package '%s'",
-		pkgs.UniquePackageName(relPkg, strictRelPkg),
-		pkgs.UniquePackageName(relImp, strictRelImp))
-}
-
-func checkDB(relPkg, strictRelPkg, relImp, strictRelImp string, cfg config.Config) error {
-	if isTestPackage(relPkg, strictRelPkg) {
-		return nil
-	}
-	if _, full := isPackageInList(cfg.Tool, nil, relImp, strictRelImp); full {
-		return nil
-	}
-	return fmt.Errorf("DB package '%s' isn't allowed to import package '%s'",
-		pkgs.UniquePackageName(relPkg, strictRelPkg),
-		pkgs.UniquePackageName(relImp, strictRelImp))
-}
-
-func checkHalfDB(relPkg, strictRelPkg, relImp, strictRelImp string, cfg config.Config) error {
-	if isTestPackage(relPkg, strictRelPkg) {
-		return nil
-	}
-	if _, full := isPackageInList(cfg.Tool, nil, relImp, strictRelImp); full {
-		return nil
-	}
-	return fmt.Errorf("DB sub-package '%s' isn't allowed to import package '%s'",
-		pkgs.UniquePackageName(relPkg, strictRelPkg),
-		pkgs.UniquePackageName(relImp, strictRelImp))
-}
-
-func checkGod(relPkg, strictRelPkg, relImp, strictRelImp string, cfg config.Config) error {
-	return nil // God never fails ;-)
-}
-
-func checkStandard(relPkg, strictRelPkg, relImp, strictRelImp string, cfg config.Config) error {
-	if isTestPackage(relPkg, strictRelPkg) {
-		return nil
-	}
-	if _, full := isPackageInList(cfg.Tool, nil, relImp, strictRelImp); full {
-		return nil
-	}
-	if _, full := isPackageInList(cfg.DB, nil, relImp, strictRelImp); full {
-		return nil
+// onlyGenerated returns true if the import is used only in generated files.
+func onlyGenerated(imps []fileImport) bool {
+	for _, imp := range imps {
+		if !imp.generated {
+			return false
+		}
 	}
-	return fmt.Errorf("domain package '%s' isn't allowed to import package '%s'",
-		pkgs.UniquePackageName(relPkg, strictRelPkg),
-		pkgs.UniquePackageName(relImp, strictRelImp))
+	return true
 }
 
-func isTestPackage(rel, strict string) bool {
-	p := strict
-	if p == "" {
-		p = rel
+// report turns the findings into violations unless they are suppressed.
+func report(check, unqPkg string, imps map[string][]fileImport, fs []rules.Finding) (errs []error) {
+	for _, f := range fs {
+		v := newViolation(check, unqPkg, f.Imp, f.Err, false)
+		if !suppress(imps[f.Path], false, v) {
+			errs = append(errs, v)
+		}
 	}
-	return strings.HasSuffix(p, "_test")
+	return errs
 }
 
-func isPackageInList(pl data.PatternList, dollars []string, pkg, strictPkg string) (atAll, full bool) {
-	if strictPkg != "" {
-		if atAll, full := pl.MatchString(strictPkg, dollars); atAll {
-			return true, full
-		}
+func newViolation(check, unqPkg, unqImp string, err error, generated bool) *data.Violation {
+	v := &data.Violation{Check: check, Pkg: unqPkg, Target: unqImp, Msg: err.Error()}
+	if generated {
+		v.Check += "-generated"
+		v.Msg = "generated code: " + v.Msg
 	}
-	return pl.MatchString(pkg, dollars)
+	return v
 }
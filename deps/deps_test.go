@@ -1,21 +1,27 @@
 package deps_test
 
 import (
+	"bytes"
+	"log"
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/flowdev/spaghetti-cutter/config"
 	"github.com/flowdev/spaghetti-cutter/deps"
 	"github.com/flowdev/spaghetti-cutter/parse"
+	"github.com/flowdev/spaghetti-cutter/testutil"
 	"github.com/flowdev/spaghetti-cutter/x/pkgs"
 )
 
 func TestCheck(t *testing.T) {
 	specs := []struct {
-		name           string
-		givenRoot      string
-		givenConfig    string
-		expectedErrors int
+		name            string
+		givenRoot       string
+		givenConfig     string
+		expectedErrors  int
+		expectedMessage string // one of the errors, if given
 	}{
 		{
 			name:           "no-config-one-pkg",
@@ -47,6 +53,11 @@ func TestCheck(t *testing.T) {
 			givenRoot:      "complex-proj",
 			givenConfig:    `{"tool": ["pkg/x/*"], "db": ["pkg/db/*"], "allowAdditionally": {"pkg/db/store": ["pkg/db/model"]} }`,
 			expectedErrors: 1,
+		}, {
+			name:           "exclude-config-complex-proj",
+			givenRoot:      "complex-proj",
+			givenConfig:    `{"tool": ["pkg/x/*"], "db": ["pkg/db/*"], "allowAdditionally": {"pkg/db/store": ["pkg/db/model"]}, "exclude": ["pkg/domain4", "cmd/**"] }`,
+			expectedErrors: 0,
 		}, {
 			name:      "allowOnlyIn-config-complex-proj",
 			givenRoot: "complex-proj",
@@ -65,6 +76,19 @@ func TestCheck(t *testing.T) {
 						"allowAdditionally": {"pkg/db/store": ["pkg/db/model"]}
 					}`,
 			expectedErrors: 1,
+			expectedMessage: "package 'pkg/domain4' isn't allowed to import package 'pkg/domain3' " +
+				"(because of allowOnlyIn `pkg/domain3` in bad-allowOnlyIn-config-complex-proj:2)",
+		}, {
+			name:      "reason-allowOnlyIn-config-complex-proj",
+			givenRoot: "complex-proj",
+			givenConfig: `{
+						"allowOnlyIn": {"pkg/domain3": {"reason": "domain3 is for exe2 only", "patterns": ["cmd/exe2"]}},
+						"tool": ["pkg/x/*"], "db": ["pkg/db/*"]
+						"allowAdditionally": {"pkg/db/store": ["pkg/db/model"]}
+					}`,
+			expectedErrors: 1,
+			expectedMessage: "package 'pkg/domain4' isn't allowed to import package 'pkg/domain3' " +
+				"(because of allowOnlyIn `pkg/domain3` in reason-allowOnlyIn-config-complex-proj:2: domain3 is for exe2 only)",
 		}, {
 			name:      "explicit-config-complex-proj",
 			givenRoot: "complex-proj",
@@ -79,11 +103,126 @@ func TestCheck(t *testing.T) {
 						"noGod": true
 					}`,
 			expectedErrors: 0,
+		}, {
+			name:      "deny-config-complex-proj",
+			givenRoot: "complex-proj",
+			givenConfig: `{
+						"tool": ["pkg/x/*"], "db": ["pkg/db/*"],
+						"allowAdditionally": {"pkg/db/store": ["pkg/db/model"]},
+						"deny": {
+						  "cmd/exe1": [{"pattern": "pkg/db/**", "reason": "use the domain packages"}],
+						  "pkg/$*": ["pkg/x/tool2"]
+						}
+					}`,
+			expectedErrors: 4,
+			expectedMessage: "package 'cmd/exe1' isn't allowed to import package 'pkg/db/store' " +
+				"(because of deny `cmd/exe1`: `pkg/db/**` in deny-config-complex-proj:5: use the domain packages)",
+		}, {
+			name:      "deny-allowed-config-complex-proj",
+			givenRoot: "complex-proj",
+			givenConfig: `{
+						"tool": ["pkg/x/*"], "db": ["pkg/db/*"],
+						"allowAdditionally": {"pkg/db/store": ["pkg/db/model"], "cmd/exe2": ["pkg/domain3"]},
+						"deny": {"cmd/exe2": ["pkg/domain3"]}
+					}`,
+			expectedErrors: 2,
+		}, {
+			name:      "deny-std-config-complex-proj",
+			givenRoot: "complex-proj",
+			givenConfig: `{
+						"tool": ["pkg/x/*"], "db": ["pkg/db/*"],
+						"allowAdditionally": {"pkg/db/store": ["pkg/db/model"]},
+						"deny": {"cmd/*": ["std:**"]}
+					}`,
+			expectedErrors: 5,
+			expectedMessage: "package 'cmd/exe1' isn't allowed to import package 'log' " +
+				"(because of deny `cmd/*`: `std:**` in deny-std-config-complex-proj:4)",
+		}, {
+			name:      "forbidden-imports-config-complex-proj",
+			givenRoot: "complex-proj",
+			givenConfig: `{
+						"tool": ["pkg/x/*"], "db": ["pkg/db/*"],
+						"allowAdditionally": {"pkg/db/store": ["pkg/db/model"]},
+						"types": [
+							{"name": "tool", "forbiddenImports": [{"pattern": "std:log", "reason": "tools return errors"}]}
+						]
+					}`,
+			expectedErrors: 3,
+			expectedMessage: "tool package 'pkg/x/tool' isn't allowed to import package 'log' " +
+				"(because of types `tool` forbiddenImports `std:log` in forbidden-imports-config-complex-proj:5: tools return errors)",
+		}, {
+			name:      "forbidden-ext-imports-config-complex-proj",
+			givenRoot: "complex-proj",
+			givenConfig: `{
+						"tool": ["pkg/x/*"], "db": ["pkg/db/*"],
+						"allowAdditionally": {"pkg/db/store": ["pkg/db/model"]},
+						"types": [{"name": "god", "mayImport": ["*"], "forbiddenImports": ["ext:**"]}]
+					}`,
+			expectedErrors: 1,
+		}, {
+			name:           "no-config-layers-proj",
+			givenRoot:      "layers-proj",
+			givenConfig:    `{}`,
+			expectedErrors: 4,
+		}, {
+			name:      "layers-config-layers-proj",
+			givenRoot: "layers-proj",
+			givenConfig: `{
+						"layers": [
+							{"name": "adapters", "patterns": ["adapter/*"]},
+							{"name": "app", "patterns": ["app"]},
+							{"name": "domain", "patterns": ["domain", "domain/*"]}
+						]
+					}`,
+			expectedErrors: 1,
+			expectedMessage: "package 'domain/event' of layer 'domain' isn't allowed to import package 'app' " +
+				"of the higher layer 'app' (because of layers `domain` in layers-config-layers-proj:5)",
+		}, {
+			name:      "strict-layers-config-layers-proj",
+			givenRoot: "layers-proj",
+			givenConfig: `{
+						"layers": [
+							{"name": "adapters", "patterns": ["adapter/*"]},
+							{"name": "app", "patterns": ["app"]},
+							{"name": "domain", "patterns": ["domain", "domain/*"]}
+						],
+						"strictLayers": true
+					}`,
+			expectedErrors: 2,
+			expectedMessage: "package 'adapter/db' of layer 'adapters' isn't allowed to import package 'domain' " +
+				"of the layer 'domain' that isn't directly below (because of strictLayers `adapters` in strict-layers-config-layers-proj:3)",
+		}, {
+			name:      "types-config-layers-proj",
+			givenRoot: "layers-proj",
+			givenConfig: `{
+						"types": [
+							{"name": "adapter", "patterns": ["adapter/*"], "mayImport": ["app", "standard"]},
+							{"name": "app", "patterns": ["app"], "mayImport": ["standard"], "mayBeImportedBy": ["adapter"]},
+							{"name": "standard", "mayImport": ["tool"]}
+						]
+					}`,
+			expectedErrors:  1,
+			expectedMessage: "domain package 'domain/event' isn't allowed to import package 'app'",
+		}, {
+			name:      "imported-by-types-config-layers-proj",
+			givenRoot: "layers-proj",
+			givenConfig: `{
+						"types": [
+							{"name": "adapter", "patterns": ["adapter/*"], "mayImport": ["app", "standard"]},
+							{"name": "app", "patterns": ["app"], "mayImport": ["standard"], "mayBeImportedBy": ["adapter"]},
+							{"name": "standard", "mayImport": ["tool", "app"]}
+						]
+					}`,
+			expectedErrors: 1,
+			expectedMessage: "domain package 'domain/event' isn't allowed to import app package 'app' " +
+				"(because of types `app` mayBeImportedBy `adapter` in imported-by-types-config-layers-proj:4)",
 		}, {
 			name:           "standard-config-half-pkgs-proj",
 			givenRoot:      "half-pkgs-proj",
 			givenConfig:    `{tool: ["x/*"], db: ["db/*"], "allowAdditionally": {"db/store": ["db/model"]} }`,
 			expectedErrors: 2,
+			expectedMessage: "tool package 'x/tool' isn't allowed to import package 'x/tool/subtool' " +
+				"(because of tool `x/*` in standard-config-half-pkgs-proj:1)",
 		}, {
 			name:      "explicit-config-half-pkgs-proj",
 			givenRoot: "half-pkgs-proj",
@@ -97,6 +236,155 @@ func TestCheck(t *testing.T) {
 						}
 					}`,
 			expectedErrors: 0,
+		}, {
+			name:           "no-generated-config-generated-proj",
+			givenRoot:      "generated-proj",
+			givenConfig:    `{tool: ["x/*"]}`,
+			expectedErrors: 1,
+		}, {
+			name:            "generated-pkg-as-domain-generated-proj",
+			givenRoot:       "generated-proj",
+			givenConfig:     `{tool: ["x/*"], types: [{name: "proto", patterns: ["pb"]}]}`,
+			expectedErrors:  4,
+			expectedMessage: "domain package 'domain1' isn't allowed to import package 'pb'",
+		}, {
+			name:           "tool-config-sub-pkgs-proj",
+			givenRoot:      "sub-pkgs-proj",
+			givenConfig:    `{tool: ["x/*"]}`,
+			expectedErrors: 3,
+			expectedMessage: "god package '/' isn't allowed to import tool sub-package 'x/tool/sub' " +
+				"(only its tool package 'x/tool' may use it) (because of tool `x/*` in tool-config-sub-pkgs-proj:1)",
+		}, {
+			name:            "explicit-config-sub-pkgs-proj",
+			givenRoot:       "sub-pkgs-proj",
+			givenConfig:     `{tool: ["x/*"], allowAdditionally: {"x/tool": ["x/tool/sub"], "main": ["x/tool/sub"]}}`,
+			expectedErrors:  1,
+			expectedMessage: "domain package 'domain' isn't allowed to import package 'x/tool/sub'",
+		}, {
+			name:           "generated-pkg-generated-proj",
+			givenRoot:      "generated-proj",
+			givenConfig:    `{tool: ["x/*"], generated: ["pb"]}`,
+			expectedErrors: 1,
+		}, {
+			name:           "ignore-generated-files-generated-proj",
+			givenRoot:      "generated-proj",
+			givenConfig:    `{tool: ["x/*"], generated: ["pb"], generatedFiles: {deps: "ignore"}}`,
+			expectedErrors: 0,
+		}, {
+			name:           "separate-generated-files-generated-proj",
+			givenRoot:      "generated-proj",
+			givenConfig:    `{tool: ["x/*"], generated: ["pb"], generatedFiles: {deps: "separate"}}`,
+			expectedErrors: 1,
+		}, {
+			name:           "standard-config-suppress-proj",
+			givenRoot:      "suppress-proj",
+			givenConfig:    `{tool: ["x/*"]}`,
+			expectedErrors: 2,
+		}, {
+			name:           "allow-config-suppress-proj",
+			givenRoot:      "suppress-proj",
+			givenConfig:    `{tool: ["x/*"], allowAdditionally: {"domain1": ["domain3"]}}`,
+			expectedErrors: 1,
+		}, {
+			name:      "deny-transitive-suppress-proj",
+			givenRoot: "suppress-proj",
+			givenConfig: `{
+						tool: ["x/*"], allowAdditionally: {"domain1": ["domain3"]},
+						denyTransitive: {"domain1": ["x/tool"], "domain3": ["x/tool"]}
+					}`,
+			expectedErrors: 2,
+			expectedMessage: "package 'domain1' isn't allowed to depend on package 'x/tool' " +
+				"(import chain: domain1 -> x/tool) " +
+				"(because of denyTransitive `domain1`: `x/tool` in deny-transitive-suppress-proj:3)",
+		}, {
+			name:           "deny-suppress-proj",
+			givenRoot:      "suppress-proj",
+			givenConfig:    `{tool: ["x/*"], allowAdditionally: {"domain1": ["domain3"]}, deny: {"domain1": ["domain2"]}}`,
+			expectedErrors: 2,
+			expectedMessage: "package 'domain1' isn't allowed to import package 'domain2' " +
+				"(because of deny `domain1`: `domain2` in deny-suppress-proj:1)",
+		}, {
+			name:      "deny-transitive-complex-proj",
+			givenRoot: "complex-proj",
+			givenConfig: `{
+						"tool": ["pkg/x/*"], "db": ["pkg/db/*"], "allowAdditionally": {"pkg/db/store": ["pkg/db/model"]},
+						"denyTransitive": {"pkg/domain1": ["log", "pkg/db/model", "net/http"]}
+					}`,
+			expectedErrors: 3,
+			expectedMessage: "package 'pkg/domain1' isn't allowed to depend on package 'log' " +
+				"(import chain: pkg/domain1 -> pkg/x/tool -> log) " +
+				"(because of denyTransitive `pkg/domain1`: `log` in deny-transitive-complex-proj:3)",
+		}, {
+			name:      "no-facade-config-facade-proj",
+			givenRoot: "facade-proj",
+			givenConfig: `{
+						"allowAdditionally": {"pkg/$*": ["pkg/$1/**"], "pkg/billing": ["pkg/orders/api", "pkg/orders/db"]}
+					}`,
+			expectedErrors: 0,
+		}, {
+			name:      "facade-config-facade-proj",
+			givenRoot: "facade-proj",
+			givenConfig: `{
+						"facades": {"pkg/$*/**": ["pkg/$1/api"]},
+						"allowAdditionally": {"pkg/$*": ["pkg/$1/**"], "pkg/billing": ["pkg/orders/api", "pkg/orders/db"]}
+					}`,
+			expectedErrors: 1,
+			expectedMessage: "package 'pkg/billing' isn't allowed to import package 'pkg/orders/db' " +
+				"(only the facade `pkg/orders/api` of 'pkg/orders' may be imported from outside) " +
+				"(because of facades `pkg/$*/**` in facade-config-facade-proj:2)",
+		}, {
+			name:      "facade-with-reason-config-facade-proj",
+			givenRoot: "facade-proj",
+			givenConfig: `{
+						"facades": {"pkg/$*/**": {"reason": "modules are private", "patterns": []}},
+						"allowAdditionally": {"pkg/$*": ["pkg/$1/**"], "pkg/billing": ["pkg/orders/api", "pkg/orders/db"]}
+					}`,
+			expectedErrors: 2,
+			expectedMessage: "package 'pkg/billing' isn't allowed to import package 'pkg/orders/api' " +
+				"(no package of 'pkg/orders' may be imported from outside) " +
+				"(because of facades `pkg/$*/**` in facade-with-reason-config-facade-proj:2: modules are private)",
+		}, {
+			name:      "facade-black-box-test-facade-proj",
+			givenRoot: "facade-proj",
+			givenConfig: `{
+						"facades": {"pkg/$*/**": []},
+						"allowAdditionally": {"pkg/$*": ["pkg/$1/**"], "pkg/orders_test": ["pkg/orders/db"]}
+					}`,
+			expectedErrors: 2,
+			expectedMessage: "package 'pkg/billing' isn't allowed to import package 'pkg/orders/db' " +
+				"(no package of 'pkg/orders' may be imported from outside) " +
+				"(because of facades `pkg/$*/**` in facade-black-box-test-facade-proj:2)",
+		}, {
+			name:           "no-test-config-tests-proj",
+			givenRoot:      "tests-proj",
+			givenConfig:    `{"tool": ["pkg/x/*"]}`,
+			expectedErrors: 0,
+		}, {
+			name:           "test-only-config-tests-proj",
+			givenRoot:      "tests-proj",
+			givenConfig:    `{"tool": ["pkg/x/*"], "testOnly": ["pkg/testutil"]}`,
+			expectedErrors: 1,
+			expectedMessage: "god package '/' isn't allowed to import testOnly package 'pkg/testutil' " +
+				"(only tests may use it) (because of testOnly `pkg/testutil` in test-only-config-tests-proj:1)",
+		}, {
+			name:      "black-box-config-tests-proj",
+			givenRoot: "tests-proj",
+			givenConfig: `{
+						"tool": ["pkg/x/*"], "testOnly": ["pkg/testutil"],
+						"tests": {"mayImport": ["tool", "testOnly"]}
+					}`,
+			expectedErrors: 2,
+			expectedMessage: "test package 'pkg/domain_test' isn't allowed to import domain package 'pkg/other' " +
+				"(because of tests mayImport `tool`, `testOnly` in black-box-config-tests-proj:3)",
+		}, {
+			name:      "in-package-config-tests-proj",
+			givenRoot: "tests-proj",
+			givenConfig: `{
+						"tool": ["pkg/x/*"], "testOnly": ["pkg/testutil"],
+						"tests": {"inPackage": true}
+					}`,
+			expectedErrors:  2,
+			expectedMessage: "domain package 'pkg/domain' isn't allowed to import package 'pkg/other'",
 		},
 	}
 
@@ -107,25 +395,67 @@ func TestCheck(t *testing.T) {
 				t.Fatalf("got unexpected error: %v", err)
 			}
 
-			packs, err := parse.DirTree(mustAbs(filepath.Join("testdata", spec.givenRoot)))
+			packs, rootPkg, err := parse.DirTree(testutil.MustAbs(filepath.Join("testdata", spec.givenRoot)), cfg.Exclude)
 			if err != nil {
 				t.Fatalf("Fatal parse error: %v", err)
 			}
 
 			var errs []string
-			rootPkg := parse.RootPkg(packs)
 			t.Logf("root package: %s", rootPkg)
 			pkgInfos := pkgs.UniquePackages(packs)
 			for _, pkgInfo := range pkgInfos {
-				errs = addErrors(errs, deps.Check(pkgInfo.Pkg, rootPkg, cfg))
+				errs = addErrors(errs, deps.Check(pkgInfo.Pkg, rootPkg, nil, cfg))
+				errs = addErrors(errs, deps.CheckTests(pkgInfo, rootPkg, cfg))
 			}
 			if len(errs) != spec.expectedErrors {
 				t.Errorf("Expected %d errors but got %d: %q", spec.expectedErrors, len(errs), errs)
 			}
+			if spec.expectedMessage != "" && !testutil.ContainsString(errs, spec.expectedMessage) {
+				t.Errorf("Expected error %q but got: %q", spec.expectedMessage, errs)
+			}
 		})
 	}
 }
 
+func TestSuppressionLog(t *testing.T) {
+	root := testutil.MustAbs(filepath.Join("testdata", "suppress-proj"))
+	expectedLines := []string{
+		"INFO - suppressed at " + filepath.Join(root, "domain1", "domain1.go") +
+			":4:2 (shared validation until the validation package exists): " +
+			"domain package 'domain1' isn't allowed to import package 'domain2'",
+		"INFO - suppressed at " + filepath.Join(root, "domain3", "domain3.go") +
+			":1:1 (legacy code that will be replaced soon): domain package 'domain3' isn't allowed to import package 'domain2'",
+		"WARNING - suppression at " + filepath.Join(root, "domain3", "unused.go") +
+			":4:75 doesn't suppress anything anymore: isn't needed at all",
+	}
+
+	cfg, err := config.Parse([]byte(`{"tool": ["x/*"], "allowAdditionally": {"domain1": ["domain3"]}}`), "suppression-log")
+	if err != nil {
+		t.Fatalf("got unexpected error: %v", err)
+	}
+	packs, rootPkg, err := parse.DirTree(root, cfg.Exclude)
+	if err != nil {
+		t.Fatalf("Fatal parse error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+	for _, pkgInfo := range pkgs.UniquePackages(packs) {
+		deps.Check(pkgInfo.Pkg, rootPkg, nil, cfg)
+	}
+
+	actualLog := buf.String()
+	for _, line := range expectedLines {
+		if !strings.Contains(actualLog, line) {
+			t.Errorf("Expected log line %q but got:\n%s", line, actualLog)
+		}
+	}
+	if strings.Contains(actualLog, "suppression at "+filepath.Join(root, "domain1")) {
+		t.Errorf("Expected no unused suppression in domain1 but got:\n%s", actualLog)
+	}
+}
+
 func addErrors(allErrs []string, newErrs []error) []string {
 	for _, err := range newErrs {
 		allErrs = append(allErrs, err.Error())
@@ -133,10 +463,63 @@ func addErrors(allErrs []string, newErrs []error) []string {
 	return allErrs
 }
 
-func mustAbs(path string) string {
-	absPath, err := filepath.Abs(path)
+func TestCheckExternal(t *testing.T) {
+	specs := []struct {
+		name            string
+		givenConfig     string
+		expectedErrors  int
+		expectedMessage string // one of the errors, if given
+	}{
+		{
+			name:           "no-config",
+			givenConfig:    `{"allowAdditionally": {"pkg/b": ["pkg/a"]}}`,
+			expectedErrors: 0,
+		}, {
+			name:           "nothing-allowed",
+			givenConfig:    `{"externalAllowed": [], "allowAdditionally": {"pkg/b": ["pkg/a"]}}`,
+			expectedErrors: 1,
+			expectedMessage: "package 'pkg/a' isn't allowed to import package " +
+				"'github.com/flowdev/spaghetti-cutter/x/config' of module 'github.com/flowdev/spaghetti-cutter' " +
+				"(because it isn't in externalAllowed)",
+		}, {
+			name:           "module-allowed",
+			givenConfig:    `{"externalAllowed": ["github.com/flowdev/spaghetti-cutter"], "allowAdditionally": {"pkg/b": ["pkg/a"]}}`,
+			expectedErrors: 0,
+		}, {
+			name:           "package-allowed",
+			givenConfig:    `{"externalAllowed": ["github.com/flowdev/spaghetti-cutter/x/dirs"], "allowAdditionally": {"pkg/b": ["pkg/a"]}}`,
+			expectedErrors: 1,
+		},
+	}
+
+	root := testutil.MustAbs(filepath.Join("testdata", "external-proj"))
+	modules, err := parse.Modules(root)
 	if err != nil {
-		panic(err.Error())
+		t.Fatalf("Fatal error listing the modules: %v", err)
+	}
+
+	for _, spec := range specs {
+		t.Run(spec.name, func(t *testing.T) {
+			cfg, err := config.Parse([]byte(spec.givenConfig), spec.name)
+			if err != nil {
+				t.Fatalf("got unexpected error: %v", err)
+			}
+
+			packs, rootPkg, err := parse.DirTree(root, cfg.Exclude)
+			if err != nil {
+				t.Fatalf("Fatal parse error: %v", err)
+			}
+
+			var errs []string
+			for _, pkgInfo := range pkgs.UniquePackages(packs) {
+				errs = addErrors(errs, deps.Check(pkgInfo.Pkg, rootPkg, modules, cfg))
+			}
+			if len(errs) != spec.expectedErrors {
+				t.Errorf("Expected %d errors but got %d: %q", spec.expectedErrors, len(errs), errs)
+			}
+			if spec.expectedMessage != "" && !testutil.ContainsString(errs, spec.expectedMessage) {
+				t.Errorf("Expected error %q but got: %q", spec.expectedMessage, errs)
+			}
+		})
 	}
-	return absPath
 }
@@ -0,0 +1,53 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/flowdev/spaghetti-cutter/config"
+	"github.com/flowdev/spaghetti-cutter/x/pkgs"
+)
+
+// CheckExternal checks that the given package imports only standard library
+// packages and external packages that are allowed by the configuration
+// (`externalAllowed`).
+// The patterns are matched against the module path (if known) and the package
+// path of the imported packages.
+// modules maps the package paths to their module paths.
+// Nothing is checked if `externalAllowed` isn't configured at all.
+func CheckExternal(
+	pkg *pkgs.Package,
+	unqPkg, rootPkg string,
+	modules map[string]string,
+	cfg config.Config,
+) (fs []Finding) {
+	if cfg.ExternalAllowed == nil {
+		return nil
+	}
+
+	for _, path := range sortedImportPaths(pkg) {
+		imp := pkg.Imports[path].PkgPath
+		if strings.HasPrefix(imp, rootPkg) || pkgs.IsStandardPackage(imp) {
+			continue
+		}
+		mod := modules[imp]
+		if mod != "" {
+			if _, full := cfg.ExternalAllowed.MatchString(mod, nil); full {
+				continue
+			}
+		}
+		if _, full := cfg.ExternalAllowed.MatchString(imp, nil); full {
+			continue
+		}
+
+		of := ""
+		if mod != "" {
+			of = fmt.Sprintf(" of module '%s'", mod)
+		}
+		fs = append(fs, Finding{Path: path, Imp: imp, Err: fmt.Errorf(
+			"package '%s' isn't allowed to import package '%s'%s (because it isn't in externalAllowed)",
+			unqPkg, imp, of,
+		)})
+	}
+	return fs
+}
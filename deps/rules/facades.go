@@ -0,0 +1,74 @@
+package rules
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/flowdev/spaghetti-cutter/config"
+	"github.com/flowdev/spaghetti-cutter/data"
+	"github.com/flowdev/spaghetti-cutter/x/pkgs"
+)
+
+// varRegexp matches the variables of patterns.
+var varRegexp = regexp.MustCompile(`\$(?:\*\*?|[1-9])`)
+
+// CheckFacade checks that packages outside of a module import only the facade
+// packages of the module.
+// It works like the visibility of `internal` packages in Go: The packages of
+// the module are the root package of the facade key and all packages below it
+// (including their black-box tests).
+func CheckFacade(relPkg, strictRelPkg, relImp, strictRelImp string, cfg config.Config) error {
+	group, facade := cfg.Facades.MatchKeyValue(relImp, strictRelImp, relImp, strictRelImp)
+	if group == nil || facade != nil {
+		return nil
+	}
+	unqImp := pkgs.UniquePackageName(relImp, strictRelImp)
+	dollars := group.Left.Regexp.FindStringSubmatch(unqImp)
+	if dollars == nil {
+		dollars = group.Left.Regexp.FindStringSubmatch(relImp)
+	}
+	if dollars == nil {
+		return nil
+	}
+	dollars = dollars[1:]
+
+	root := expandVariables(config.FacadeRoot(group.Left.Pattern), dollars)
+	for _, pkg := range []string{strictRelPkg, relPkg} {
+		pkg = strings.TrimSuffix(pkg, "_test") // black-box tests belong to their package
+		if pkg == root || strings.HasPrefix(pkg, root+"/") {
+			return nil // inside of the module
+		}
+	}
+
+	visible := fmt.Sprintf("no package of '%s' may be imported from outside", root)
+	if len(group.Right) > 0 {
+		facades := make([]string, len(group.Right))
+		for i, p := range group.Right {
+			facades[i] = expandVariables(p.Pattern, dollars)
+		}
+		visible = fmt.Sprintf("only the facade `%s` of '%s' may be imported from outside",
+			strings.Join(facades, "`, `"), root)
+	}
+	return fmt.Errorf("package '%s' isn't allowed to import package '%s' (%s)%s",
+		pkgs.UniquePackageName(relPkg, strictRelPkg), unqImp, visible,
+		data.Because("facades", "`"+group.Left.Pattern+"`", group.Left.Location, group.Left.Reason))
+}
+
+// expandVariables replaces the variables of the pattern with the given values
+// (`$*` and `$**` in order of appearance and `$1` ... `$9` by number).
+func expandVariables(pattern string, dollars []string) string {
+	n := 0
+	return varRegexp.ReplaceAllStringFunc(pattern, func(v string) string {
+		i := n
+		if d := v[1]; d >= '1' && d <= '9' {
+			i = int(d - '1')
+		} else {
+			n++
+		}
+		if i < len(dollars) {
+			return dollars[i]
+		}
+		return v
+	})
+}
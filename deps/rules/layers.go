@@ -0,0 +1,54 @@
+package rules
+
+import (
+	"fmt"
+
+	"github.com/flowdev/spaghetti-cutter/config"
+	"github.com/flowdev/spaghetti-cutter/data"
+	"github.com/flowdev/spaghetti-cutter/x/pkgs"
+)
+
+// LayerOf returns the index of the first layer that contains the package or
+// -1 if the package isn't part of any layer.
+func LayerOf(relPkg, strictRelPkg string, cfg config.Config) int {
+	for i, layer := range cfg.Layers {
+		if _, full := layer.Patterns.MatchPackage(relPkg, strictRelPkg); full {
+			return i
+		}
+	}
+	return -1
+}
+
+// CheckLayers checks that a package only imports packages of the same or
+// lower layers (strict: only the same or the directly lower layer).
+// The layers only decide (layered is true) if both packages are part of a
+// layer. Otherwise the package types decide.
+func CheckLayers(relPkg, strictRelPkg, relImp, strictRelImp string, cfg config.Config) (layered bool, err error) {
+	if len(cfg.Layers) == 0 {
+		return false, nil
+	}
+	pkgLayer := LayerOf(relPkg, strictRelPkg, cfg)
+	impLayer := LayerOf(relImp, strictRelImp, cfg)
+	if pkgLayer < 0 || impLayer < 0 {
+		return false, nil
+	}
+	if isTestPackage(relPkg, strictRelPkg) {
+		return cfg.Tests.MayImport == nil, nil // otherwise the policy for tests decides
+	}
+
+	layer := cfg.Layers[pkgLayer]
+	switch {
+	case impLayer < pkgLayer:
+		return true, fmt.Errorf("package '%s' of layer '%s' isn't allowed to import package '%s' of the higher layer '%s'%s",
+			pkgs.UniquePackageName(relPkg, strictRelPkg), layer.Name,
+			pkgs.UniquePackageName(relImp, strictRelImp), cfg.Layers[impLayer].Name,
+			data.Because("layers", "`"+layer.Name+"`", layer.Location, ""))
+	case cfg.StrictLayers && impLayer > pkgLayer+1:
+		return true, fmt.Errorf("package '%s' of layer '%s' isn't allowed to import package '%s' of the layer '%s' "+
+			"that isn't directly below%s",
+			pkgs.UniquePackageName(relPkg, strictRelPkg), layer.Name,
+			pkgs.UniquePackageName(relImp, strictRelImp), cfg.Layers[impLayer].Name,
+			data.Because("strictLayers", "`"+layer.Name+"`", layer.Location, ""))
+	}
+	return true, nil
+}
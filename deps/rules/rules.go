@@ -0,0 +1,21 @@
+// Package rules contains the rules for the dependencies of packages.
+// The rules only find forbidden dependencies. Reporting them (including
+// suppressions) is the job of package deps.
+package rules
+
+import "strings"
+
+// Finding is a forbidden dependency that has been found.
+type Finding struct {
+	Path string // import path of the direct import that leads to the dependency
+	Imp  string // name of the forbidden package
+	Err  error
+}
+
+func isTestPackage(rel, strict string) bool {
+	p := strict
+	if p == "" {
+		p = rel
+	}
+	return strings.HasSuffix(p, "_test")
+}
@@ -0,0 +1,85 @@
+package rules
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/flowdev/spaghetti-cutter/config"
+	"github.com/flowdev/spaghetti-cutter/data"
+	"github.com/flowdev/spaghetti-cutter/x/pkgs"
+)
+
+// CheckTransitive checks the denyTransitive rules of the package against all
+// packages it depends on directly or indirectly (including external and
+// standard library packages).
+// For every forbidden package the shortest import chain is found.
+func CheckTransitive(pkg *pkgs.Package, relPkg, strictRelPkg, rootPkg string, cfg config.Config) (fs []Finding) {
+	if group, _ := cfg.DenyTransitive.MatchKeyValue(relPkg, strictRelPkg, "", ""); group == nil {
+		return nil
+	}
+	unqPkg := pkgs.UniquePackageName(relPkg, strictRelPkg)
+
+	// breadth first search finds the shortest chains
+	parents := map[string]*pkgs.Package{pkg.PkgPath: nil}
+	via := map[string]string{} // the direct import (path) that leads to a package
+	queue := []*pkgs.Package{pkg}
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+
+		for _, path := range sortedImportPaths(p) {
+			imp := p.Imports[path]
+			if _, ok := parents[imp.PkgPath]; ok {
+				continue
+			}
+			parents[imp.PkgPath] = p
+			via[imp.PkgPath] = via[p.PkgPath]
+			if p == pkg {
+				via[imp.PkgPath] = path
+			}
+			queue = append(queue, imp)
+
+			relImp, strictRelImp := pkgs.RelativeImportName(imp, rootPkg)
+			group, deny := cfg.DenyTransitive.MatchKeyValue(relPkg, strictRelPkg, relImp, strictRelImp)
+			if deny == nil {
+				continue
+			}
+			reason := deny.Reason
+			if reason == "" {
+				reason = group.Left.Reason
+			}
+			unqImp := pkgs.UniquePackageName(relImp, strictRelImp)
+			fs = append(fs, Finding{Path: via[imp.PkgPath], Imp: unqImp, Err: fmt.Errorf(
+				"package '%s' isn't allowed to depend on package '%s' (import chain: %s)%s",
+				unqPkg, unqImp, importChain(imp, parents, rootPkg),
+				data.Because("denyTransitive", "`"+group.Left.Pattern+"`: `"+deny.Pattern+"`", deny.Location, reason),
+			)})
+		}
+	}
+	return fs
+}
+
+// importChain returns the chain of imports from the start of the search to
+// the given package.
+func importChain(imp *pkgs.Package, parents map[string]*pkgs.Package, rootPkg string) string {
+	var chain []string
+	for p := imp; p != nil; p = parents[p.PkgPath] {
+		chain = append(chain, pkgs.UniquePackageName(pkgs.RelativeImportName(p, rootPkg)))
+	}
+	for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+		chain[i], chain[j] = chain[j], chain[i]
+	}
+	return strings.Join(chain, " -> ")
+}
+
+// sortedImportPaths returns the import paths of the package in a stable order,
+// so the reported chains don't change between runs.
+func sortedImportPaths(pkg *pkgs.Package) []string {
+	paths := make([]string, 0, len(pkg.Imports))
+	for path := range pkg.Imports {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
@@ -0,0 +1,85 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/flowdev/spaghetti-cutter/config"
+	"github.com/flowdev/spaghetti-cutter/data"
+	"github.com/flowdev/spaghetti-cutter/x/pkgs"
+)
+
+// CheckTypes checks the import policies of the types of both packages.
+// Sub-packages of tool and DB packages may only be used by their parent
+// package and testOnly packages only by tests.
+// inTest is true if the import is used only by the in-package tests.
+func CheckTypes(
+	pkgT, impT config.TypeInfo,
+	inTest bool,
+	relPkg, strictRelPkg, relImp, strictRelImp string,
+	tests config.Tests,
+) error {
+	unqPkg := pkgs.UniquePackageName(relPkg, strictRelPkg)
+	unqImp := pkgs.UniquePackageName(relImp, strictRelImp)
+	blackBox := isTestPackage(relPkg, strictRelPkg)
+
+	if impT.Type == pkgs.PkgTypeTestOnly {
+		if inTest || blackBox {
+			return nil
+		}
+		if pkgT.Type != pkgs.PkgTypeTestOnly {
+			return fmt.Errorf("%s '%s' isn't allowed to import %s '%s' (only tests may use it)%s",
+				config.TypeLabel(pkgT.Type), unqPkg, config.TypeLabel(impT.Type), unqImp, impT.Because())
+		}
+	}
+	if blackBox {
+		return checkBlackBoxTest(impT, unqPkg, unqImp, tests)
+	}
+
+	if !config.ContainsType(pkgT.Def.MayImport, impT.Type) {
+		return fmt.Errorf("%s '%s' isn't allowed to import package '%s'%s",
+			config.TypeLabel(pkgT.Type), unqPkg, unqImp, pkgT.Because())
+	}
+	if by := impT.Def.MayBeImportedBy; len(by) > 0 && !config.ContainsType(by, pkgT.Type) {
+		return fmt.Errorf("%s '%s' isn't allowed to import %s '%s'%s",
+			config.TypeLabel(pkgT.Type), unqPkg, config.TypeLabel(impT.Type), unqImp,
+			data.Because("types", "`"+impT.Def.Name+"` mayBeImportedBy "+config.TypeNames(by), impT.Def.Location, ""))
+	}
+	if impT.IsSubPackage() {
+		parent := impT.Parent(relImp, strictRelImp)
+		if parent != relPkg && parent != strictRelPkg {
+			return fmt.Errorf("%s '%s' isn't allowed to import %s '%s' (only its %s '%s' may use it)%s",
+				config.TypeLabel(pkgT.Type), unqPkg, config.TypeLabel(impT.Type), unqImp,
+				config.TypeLabel(pkgs.PkgType(impT.Def.Name)), parent, impT.Because())
+		}
+	}
+	return nil
+}
+
+// checkBlackBoxTest checks that a black-box test package (`_test`) imports
+// only the package under test and packages of the types that tests may import.
+func checkBlackBoxTest(impT config.TypeInfo, unqPkg, unqImp string, tests config.Tests) error {
+	if tests.MayImport == nil || unqImp == strings.TrimSuffix(unqPkg, "_test") ||
+		config.ContainsType(tests.MayImport, impT.Type) {
+		return nil
+	}
+	return fmt.Errorf("test package '%s' isn't allowed to import %s '%s'%s",
+		unqPkg, config.TypeLabel(impT.Type), unqImp,
+		data.Because("tests", "mayImport "+config.TypeNames(tests.MayImport), tests.Location, ""))
+}
+
+// CheckForbiddenImport checks that a package doesn't import a standard library
+// or external package that is forbidden for its type.
+func CheckForbiddenImport(pkgT config.TypeInfo, relPkg, strictRelPkg, relImp, strictRelImp string) error {
+	if isTestPackage(relPkg, strictRelPkg) {
+		return nil
+	}
+	p, full := pkgT.Def.ForbiddenImports.MatchPackage(relImp, strictRelImp)
+	if !full {
+		return nil
+	}
+	return fmt.Errorf("%s '%s' isn't allowed to import package '%s'%s",
+		config.TypeLabel(pkgT.Type), pkgs.UniquePackageName(relPkg, strictRelPkg),
+		pkgs.UniquePackageName(relImp, strictRelImp),
+		data.Because("types", "`"+pkgT.Def.Name+"` forbiddenImports `"+p.Pattern+"`", p.Location, p.Reason))
+}
@@ -0,0 +1,139 @@
+package deps
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"log"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/flowdev/spaghetti-cutter/data"
+	"github.com/flowdev/spaghetti-cutter/x/pkgs"
+)
+
+const (
+	allowDirective     = "//spaghetti:allow"
+	allowFileDirective = "//spaghetti:allow-file"
+)
+
+// suppression is a `//spaghetti:allow <reason>` comment on an import spec or
+// a `//spaghetti:allow-file <reason>` comment before the package clause.
+type suppression struct {
+	pos    token.Position
+	reason string
+	used   bool
+}
+
+// fileImport is the import of a package in a single file.
+type fileImport struct {
+	generated bool
+	supp      *suppression // nil if the import isn't suppressed
+}
+
+// fileImports returns all imports of the given package per import path
+// together with all suppressions and errors for suppressions without a reason.
+// If testFilesOnly is true, only the `_test.go` files of the package are used.
+func fileImports(pkg *pkgs.Package, unqPkg string, testFilesOnly bool,
+) (map[string][]fileImport, []*suppression, []error) {
+	imps := make(map[string][]fileImport, len(pkg.Imports))
+	var supps []*suppression
+	var errs []error
+
+	newSuppression := func(c *ast.Comment, directive string) *suppression {
+		s := &suppression{
+			pos:    pkg.Fset.Position(c.Pos()),
+			reason: strings.TrimSpace(c.Text[len(directive):]),
+		}
+		if s.reason == "" {
+			errs = append(errs, &data.Violation{
+				Check:  "suppression",
+				Pkg:    unqPkg,
+				Target: filepath.Base(s.pos.Filename),
+				Msg:    fmt.Sprintf("the reason is missing for the suppression at %s", s.pos),
+			})
+			return nil
+		}
+		supps = append(supps, s)
+		return s
+	}
+
+	for _, astf := range pkg.Syntax {
+		if testFilesOnly && !strings.HasSuffix(pkg.Fset.Position(astf.Package).Filename, "_test.go") {
+			continue
+		}
+		generated := pkgs.IsGeneratedFile(astf)
+		var fileSupp *suppression
+		for _, cg := range astf.Comments {
+			if cg.Pos() >= astf.Package {
+				break
+			}
+			if c := findDirective(cg, allowFileDirective); c != nil {
+				fileSupp = newSuppression(c, allowFileDirective)
+			}
+		}
+
+		for _, spec := range astf.Imports {
+			path, err := strconv.Unquote(spec.Path.Value)
+			if err != nil {
+				continue
+			}
+			supp := fileSupp
+			for _, cg := range []*ast.CommentGroup{spec.Doc, spec.Comment} {
+				if c := findDirective(cg, allowDirective); c != nil {
+					supp = newSuppression(c, allowDirective)
+				}
+			}
+			imps[path] = append(imps[path], fileImport{generated: generated, supp: supp})
+		}
+	}
+	return imps, supps, errs
+}
+
+func findDirective(cg *ast.CommentGroup, directive string) *ast.Comment {
+	if cg == nil {
+		return nil
+	}
+	for _, c := range cg.List {
+		if c.Text == directive || strings.HasPrefix(c.Text, directive+" ") {
+			return c
+		}
+	}
+	return nil
+}
+
+// suppress returns true if the import of the given path is suppressed in all
+// files of the package that are relevant.
+// All relevant suppressions are marked as used.
+func suppress(imps []fileImport, splitGenerated bool, err error) bool {
+	n := 0
+	for _, imp := range imps {
+		if splitGenerated && imp.generated {
+			continue
+		}
+		if imp.supp == nil {
+			return false
+		}
+		n++
+	}
+	if n == 0 {
+		return false
+	}
+	for _, imp := range imps {
+		if splitGenerated && imp.generated {
+			continue
+		}
+		log.Printf("INFO - suppressed at %s (%s): %v", imp.supp.pos, imp.supp.reason, err)
+		imp.supp.used = true
+	}
+	return true
+}
+
+func reportUnusedSuppressions(supps []*suppression) {
+	for _, s := range supps {
+		if !s.used {
+			log.Printf("WARNING - suppression at %s doesn't suppress anything anymore: %s", s.pos, s.reason)
+		}
+	}
+}
@@ -0,0 +1,11 @@
+package main
+
+import (
+	"github.com/flowdev/spaghetti-cutter/deps/testdata/external-proj/pkg/b"
+	"github.com/flowdev/spaghetti-cutter/x/dirs" //spaghetti:allow the command finds the project root
+)
+
+func main() {
+	b.B()
+	_ = dirs.FindRoot
+}
@@ -0,0 +1,8 @@
+package a
+
+import "github.com/flowdev/spaghetti-cutter/x/config"
+
+// A is using an external package.
+func A() string {
+	return config.File
+}
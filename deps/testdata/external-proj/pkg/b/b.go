@@ -0,0 +1,12 @@
+package b
+
+import (
+	"log"
+
+	"github.com/flowdev/spaghetti-cutter/deps/testdata/external-proj/pkg/a"
+)
+
+// B is using only internal and standard library packages.
+func B() {
+	log.Printf("INFO - b.B: %s", a.A())
+}
@@ -0,0 +1,3 @@
+module github.com/flowdev/spaghetti-cutter/deps/testdata/facade-proj
+
+go 1.14
@@ -0,0 +1,11 @@
+package main
+
+import (
+	"github.com/flowdev/spaghetti-cutter/deps/testdata/facade-proj/pkg/billing"
+	"github.com/flowdev/spaghetti-cutter/deps/testdata/facade-proj/pkg/orders"
+)
+
+func main() {
+	orders.Order()
+	billing.Bill()
+}
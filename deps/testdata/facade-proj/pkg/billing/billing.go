@@ -0,0 +1,12 @@
+package billing
+
+import (
+	"github.com/flowdev/spaghetti-cutter/deps/testdata/facade-proj/pkg/orders/api"
+	"github.com/flowdev/spaghetti-cutter/deps/testdata/facade-proj/pkg/orders/db"
+)
+
+// Bill is just here to be called.
+func Bill() {
+	_ = api.Status()
+	db.Store()
+}
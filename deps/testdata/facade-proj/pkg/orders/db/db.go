@@ -0,0 +1,5 @@
+package db
+
+// Store is just here to be called.
+func Store() {
+}
@@ -0,0 +1,8 @@
+package orders
+
+import "github.com/flowdev/spaghetti-cutter/deps/testdata/facade-proj/pkg/orders/db"
+
+// Order is just here to be called.
+func Order() {
+	db.Store()
+}
@@ -0,0 +1,13 @@
+package orders_test
+
+import (
+	"testing"
+
+	"github.com/flowdev/spaghetti-cutter/deps/testdata/facade-proj/pkg/orders"
+	"github.com/flowdev/spaghetti-cutter/deps/testdata/facade-proj/pkg/orders/db"
+)
+
+func TestOrder(t *testing.T) {
+	orders.Order()
+	db.Store()
+}
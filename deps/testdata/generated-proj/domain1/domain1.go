@@ -0,0 +1,11 @@
+package domain1
+
+import (
+	"github.com/flowdev/spaghetti-cutter/deps/testdata/generated-proj/pb"
+	"github.com/flowdev/spaghetti-cutter/deps/testdata/generated-proj/x/tool"
+)
+
+func HandleDomain1Route1(req *pb.Request) {
+	tool.Tool()
+	req.Validate()
+}
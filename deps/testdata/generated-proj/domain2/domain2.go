@@ -0,0 +1,10 @@
+package domain2
+
+import (
+	"github.com/flowdev/spaghetti-cutter/deps/testdata/generated-proj/x/tool"
+)
+
+func IsValid(name string) bool {
+	tool.Tool()
+	return name != ""
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/flowdev/spaghetti-cutter/deps/testdata/generated-proj/domain1"
+	"github.com/flowdev/spaghetti-cutter/deps/testdata/generated-proj/pb"
+)
+
+func main() {
+	doIt(os.Args[1:])
+}
+
+func doIt(args []string) {
+	log.Printf("INFO - this is the main package, args: %q", args)
+	domain1.HandleDomain1Route1(&pb.Request{})
+	domain1.HandleDomain1Route2()
+}
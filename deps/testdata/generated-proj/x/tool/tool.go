@@ -0,0 +1,8 @@
+package tool
+
+import "log"
+
+// Tool is logging its execution.
+func Tool() {
+	log.Printf("INFO - tool.Tool")
+}
@@ -0,0 +1,8 @@
+package db
+
+import "github.com/flowdev/spaghetti-cutter/deps/testdata/layers-proj/domain"
+
+// Open opens the database and stores a domain object.
+func Open() {
+	domain.Store()
+}
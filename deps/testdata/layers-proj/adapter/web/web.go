@@ -0,0 +1,8 @@
+package web
+
+import "github.com/flowdev/spaghetti-cutter/deps/testdata/layers-proj/app"
+
+// Serve serves the application.
+func Serve() {
+	app.Run()
+}
@@ -0,0 +1,8 @@
+package app
+
+import "github.com/flowdev/spaghetti-cutter/deps/testdata/layers-proj/domain"
+
+// Run runs the use cases.
+func Run() {
+	domain.Store()
+}
@@ -0,0 +1,5 @@
+package domain
+
+// Store stores a domain object.
+func Store() {
+}
@@ -0,0 +1,8 @@
+package event
+
+import "github.com/flowdev/spaghetti-cutter/deps/testdata/layers-proj/app"
+
+// Publish publishes an event and runs the application again.
+func Publish() {
+	app.Run()
+}
@@ -0,0 +1,11 @@
+package main
+
+import (
+	"github.com/flowdev/spaghetti-cutter/deps/testdata/layers-proj/adapter/db"
+	"github.com/flowdev/spaghetti-cutter/deps/testdata/layers-proj/adapter/web"
+)
+
+func main() {
+	db.Open()
+	web.Serve()
+}
@@ -0,0 +1,8 @@
+package domain
+
+import "github.com/flowdev/spaghetti-cutter/deps/testdata/sub-pkgs-proj/x/tool/sub"
+
+// Do does it.
+func Do() {
+	sub.Help()
+}
@@ -0,0 +1,13 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/flowdev/spaghetti-cutter/deps/testdata/sub-pkgs-proj/domain"
+	"github.com/flowdev/spaghetti-cutter/deps/testdata/sub-pkgs-proj/x/tool/sub"
+)
+
+func TestDo(t *testing.T) {
+	sub.Help()
+	domain.Do()
+}
@@ -0,0 +1,3 @@
+module github.com/flowdev/spaghetti-cutter/deps/testdata/sub-pkgs-proj
+
+go 1.14
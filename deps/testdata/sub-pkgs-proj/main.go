@@ -0,0 +1,13 @@
+package main
+
+import (
+	"github.com/flowdev/spaghetti-cutter/deps/testdata/sub-pkgs-proj/domain"
+	"github.com/flowdev/spaghetti-cutter/deps/testdata/sub-pkgs-proj/x/tool"
+	"github.com/flowdev/spaghetti-cutter/deps/testdata/sub-pkgs-proj/x/tool/sub"
+)
+
+func main() {
+	domain.Do()
+	tool.Help()
+	sub.Help()
+}
@@ -0,0 +1,5 @@
+package sub
+
+// Help helps.
+func Help() {
+}
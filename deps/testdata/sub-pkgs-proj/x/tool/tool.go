@@ -0,0 +1,8 @@
+package tool
+
+import "github.com/flowdev/spaghetti-cutter/deps/testdata/sub-pkgs-proj/x/tool/sub"
+
+// Help helps using the sub-package.
+func Help() {
+	sub.Help()
+}
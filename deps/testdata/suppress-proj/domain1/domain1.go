@@ -0,0 +1,12 @@
+package domain1
+
+import (
+	//spaghetti:allow shared validation until the validation package exists
+	"github.com/flowdev/spaghetti-cutter/deps/testdata/suppress-proj/domain2"
+	"github.com/flowdev/spaghetti-cutter/deps/testdata/suppress-proj/x/tool"
+)
+
+func HandleDomain1Route1() {
+	tool.Tool()
+	domain2.IsValid("route1")
+}
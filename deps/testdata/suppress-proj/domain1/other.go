@@ -0,0 +1,9 @@
+package domain1
+
+import (
+	"github.com/flowdev/spaghetti-cutter/deps/testdata/suppress-proj/domain3"
+)
+
+func HandleDomain1Route2() {
+	domain3.HandleDomain3Route1()
+}
@@ -0,0 +1,10 @@
+package domain2
+
+import (
+	"github.com/flowdev/spaghetti-cutter/deps/testdata/suppress-proj/x/tool" //spaghetti:allow
+)
+
+func IsValid(name string) bool {
+	tool.Tool()
+	return name != ""
+}
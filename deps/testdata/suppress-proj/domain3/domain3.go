@@ -0,0 +1,13 @@
+//spaghetti:allow-file legacy code that will be replaced soon
+
+package domain3
+
+import (
+	"github.com/flowdev/spaghetti-cutter/deps/testdata/suppress-proj/domain2"
+	"github.com/flowdev/spaghetti-cutter/deps/testdata/suppress-proj/x/tool"
+)
+
+func HandleDomain3Route1() {
+	tool.Tool()
+	domain2.IsValid("route1")
+}
@@ -0,0 +1,9 @@
+package domain3
+
+import (
+	"github.com/flowdev/spaghetti-cutter/deps/testdata/suppress-proj/x/tool" //spaghetti:allow isn't needed at all
+)
+
+func HandleDomain3Route2() {
+	tool.Tool()
+}
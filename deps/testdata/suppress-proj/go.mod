@@ -0,0 +1,3 @@
+module github.com/flowdev/spaghetti-cutter/deps/testdata/suppress-proj
+
+go 1.14
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/flowdev/spaghetti-cutter/deps/testdata/suppress-proj/domain1"
+	"github.com/flowdev/spaghetti-cutter/deps/testdata/suppress-proj/domain3"
+)
+
+func main() {
+	doIt(os.Args[1:])
+}
+
+func doIt(args []string) {
+	log.Printf("INFO - this is the main package, args: %q", args)
+	domain1.HandleDomain1Route1()
+	domain3.HandleDomain3Route1()
+}
@@ -0,0 +1,13 @@
+package main
+
+import (
+	"github.com/flowdev/spaghetti-cutter/deps/testdata/tests-proj/pkg/domain"
+	"github.com/flowdev/spaghetti-cutter/deps/testdata/tests-proj/pkg/other"
+	"github.com/flowdev/spaghetti-cutter/deps/testdata/tests-proj/pkg/testutil"
+)
+
+func main() {
+	domain.Domain()
+	other.Other()
+	testutil.TestUtil()
+}
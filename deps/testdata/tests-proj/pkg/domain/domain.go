@@ -0,0 +1,6 @@
+package domain
+
+// Domain is the function under test.
+func Domain() int {
+	return 1
+}
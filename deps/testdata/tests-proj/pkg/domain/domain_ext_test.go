@@ -0,0 +1,17 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/flowdev/spaghetti-cutter/deps/testdata/tests-proj/pkg/domain"
+	"github.com/flowdev/spaghetti-cutter/deps/testdata/tests-proj/pkg/other"
+	"github.com/flowdev/spaghetti-cutter/deps/testdata/tests-proj/pkg/testutil"
+	"github.com/flowdev/spaghetti-cutter/deps/testdata/tests-proj/pkg/x/tool"
+)
+
+func TestDomainExternally(t *testing.T) {
+	testutil.TestUtil()
+	if domain.Domain() != other.Other() || !tool.Tool() {
+		t.Error("unexpected result")
+	}
+}
@@ -0,0 +1,15 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/flowdev/spaghetti-cutter/deps/testdata/tests-proj/pkg/other"
+	"github.com/flowdev/spaghetti-cutter/deps/testdata/tests-proj/pkg/testutil"
+)
+
+func TestDomain(t *testing.T) {
+	testutil.TestUtil()
+	if Domain() != other.Other() {
+		t.Error("unexpected result")
+	}
+}
@@ -0,0 +1,6 @@
+package other
+
+// Other is used by the tests of the domain package.
+func Other() int {
+	return 1
+}
@@ -0,0 +1,5 @@
+package testutil
+
+// TestUtil helps the tests.
+func TestUtil() {
+}
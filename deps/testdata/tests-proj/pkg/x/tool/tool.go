@@ -0,0 +1,6 @@
+package tool
+
+// Tool is a helper.
+func Tool() bool {
+	return true
+}
@@ -0,0 +1,71 @@
+// Package fan computes the fan-in and fan-out of a package and checks them
+// against the configured maxima.
+package fan
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/flowdev/spaghetti-cutter/config"
+	"github.com/flowdev/spaghetti-cutter/data"
+	"github.com/flowdev/spaghetti-cutter/x/pkgs"
+)
+
+// Check reports the fan-in (internal packages importing the package) and the
+// fan-out (internal and external packages imported by the package) and
+// checks them against the first matching limit of the configuration.
+// The dependencies of the package have to be linked already
+// (see: pkgs.LinkPackages).
+func Check(pkgInfo *pkgs.PackageInfo, rootPkg string, cfg config.Config) []error {
+	relPkg, strictRelPkg := pkgs.RelativePackageName(pkgInfo.Pkg, rootPkg)
+	uniqPkg := pkgs.UniquePackageName(relPkg, strictRelPkg)
+	if strings.HasSuffix(uniqPkg, "_test") {
+		return nil
+	}
+	fanIn, fanOut, extFanOut := uint(len(pkgInfo.Users)), uint(len(pkgInfo.Deps)), uint(len(pkgInfo.ExtDeps))
+	log.Printf("INFO - Fan-in of package '%s': %d, fan-out: %d internal and %d external packages",
+		uniqPkg, fanIn, fanOut, extFanOut)
+
+	limit, pattern := limitOf(relPkg, strictRelPkg, cfg.FanLimits)
+	if limit == nil {
+		return nil
+	}
+	// violations are identified by the pattern of the limit and their counts are
+	// the sizes that the baseline compares
+	target := "`" + pattern.Pattern + "`"
+	because := data.Because("fanLimits", target, pattern.Location, pattern.Reason)
+	var errs []error
+	if limit.MaxFanIn > 0 && fanIn > limit.MaxFanIn {
+		errs = append(errs, &data.Violation{Check: "fan-in", Pkg: uniqPkg, Target: target, Size: fanIn, Msg: fmt.Sprintf(
+			"package '%s' is imported by %d internal packages, max %d%s",
+			uniqPkg, fanIn, limit.MaxFanIn, because)})
+	}
+	if limit.MaxFanOut > 0 && fanOut > limit.MaxFanOut {
+		errs = append(errs, &data.Violation{Check: "fan-out", Pkg: uniqPkg, Target: target, Size: fanOut, Msg: fmt.Sprintf(
+			"package '%s' imports %d internal packages, max %d%s",
+			uniqPkg, fanOut, limit.MaxFanOut, because)})
+	}
+	if limit.MaxExternalFanOut > 0 && extFanOut > limit.MaxExternalFanOut {
+		errs = append(errs, &data.Violation{Check: "fan-out-external", Pkg: uniqPkg, Target: target, Size: extFanOut, Msg: fmt.Sprintf(
+			"package '%s' imports %d external packages, max %d%s",
+			uniqPkg, extFanOut, limit.MaxExternalFanOut, because)})
+	}
+	return errs
+}
+
+// limitOf returns the first limit with a pattern matching the package and
+// the matching pattern.
+func limitOf(relPkg, strictRelPkg string, fls config.FanLimits) (*config.FanLimit, *data.Pattern) {
+	for i, fl := range fls {
+		for _, pkg := range []string{strictRelPkg, relPkg} {
+			if pkg == "" {
+				continue
+			}
+			if j, full := fl.Patterns.MatchStringIndex(pkg, nil); j >= 0 && full {
+				return &fls[i], &fl.Patterns[j]
+			}
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,107 @@
+package fan_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/flowdev/spaghetti-cutter/config"
+	"github.com/flowdev/spaghetti-cutter/data"
+	"github.com/flowdev/spaghetti-cutter/fan"
+	"github.com/flowdev/spaghetti-cutter/parse"
+	"github.com/flowdev/spaghetti-cutter/testutil"
+	"github.com/flowdev/spaghetti-cutter/x/pkgs"
+)
+
+func TestCheck(t *testing.T) {
+	specs := []struct {
+		name            string
+		givenConfig     string
+		expectedErrors  int
+		expectedMessage string // one of the errors, if given
+		expectedTarget  string // target of the error with the expected message
+		expectedSize    uint   // size of the error with the expected message
+	}{
+		{
+			name:           "no-limits",
+			givenConfig:    `{}`,
+			expectedErrors: 0,
+		}, {
+			name:           "high-limits",
+			givenConfig:    `{"fanLimits": [{"patterns": ["**"], "maxFanIn": 3, "maxFanOut": 3, "maxExternalFanOut": 2}]}`,
+			expectedErrors: 0,
+		}, {
+			name:           "fan-in",
+			givenConfig:    `{"fanLimits": [{"patterns": ["c"], "maxFanIn": 2}]}`,
+			expectedErrors: 1,
+			expectedMessage: "package 'c' is imported by 3 internal packages, max 2 " +
+				"(because of fanLimits `c` in fan-in:1)",
+			expectedTarget: "`c`",
+			expectedSize:   3,
+		}, {
+			name: "fan-out-first-limit-wins",
+			givenConfig: `{
+						"fanLimits": [
+							{"patterns": ["main"], "maxFanOut": 2},
+							{"patterns": ["**"], "maxFanOut": 1, "maxExternalFanOut": 1}
+						]
+					}`,
+			expectedErrors: 1,
+			expectedMessage: "package '/' imports 3 internal packages, max 2 " +
+				"(because of fanLimits `main` in fan-out-first-limit-wins:3)",
+			expectedTarget: "`main`",
+			expectedSize:   3,
+		}, {
+			name: "external-fan-out",
+			givenConfig: `{
+						"fanLimits": [{"patterns": [{"pattern": "**", "reason": "keep external dependencies few"}], "maxExternalFanOut": 1}]
+					}`,
+			expectedErrors: 1,
+			expectedMessage: "package '/' imports 2 external packages, max 1 " +
+				"(because of fanLimits `**` in external-fan-out:2: keep external dependencies few)",
+			expectedTarget: "`**`",
+			expectedSize:   2,
+		},
+	}
+
+	packs, rootPkg, err := parse.DirTree(testutil.MustAbs(filepath.Join("testdata", "fan-proj")), nil)
+	if err != nil {
+		t.Fatalf("Fatal parse error: %v", err)
+	}
+	pkgInfos := pkgs.UniquePackages(packs)
+	pkgs.LinkPackages(pkgInfos, rootPkg)
+
+	for _, spec := range specs {
+		t.Run(spec.name, func(t *testing.T) {
+			cfg, err := config.Parse([]byte(spec.givenConfig), spec.name)
+			if err != nil {
+				t.Fatalf("got unexpected error: %v", err)
+			}
+
+			var errs []string
+			var expected *data.Violation
+			for _, pkgInfo := range pkgInfos {
+				for _, err := range fan.Check(pkgInfo, rootPkg, cfg) {
+					errs = append(errs, err.Error())
+					if err.Error() == spec.expectedMessage {
+						expected = err.(*data.Violation)
+					}
+				}
+			}
+			if len(errs) != spec.expectedErrors {
+				t.Errorf("Expected %d errors but got %d: %q", spec.expectedErrors, len(errs), errs)
+			}
+			if spec.expectedMessage == "" {
+				return
+			}
+			if expected == nil {
+				t.Fatalf("Expected error %q but got: %q", spec.expectedMessage, errs)
+			}
+			if expected.Target != spec.expectedTarget {
+				t.Errorf("Expected target %q but got: %q", spec.expectedTarget, expected.Target)
+			}
+			if expected.Size != spec.expectedSize {
+				t.Errorf("Expected size %d but got: %d", spec.expectedSize, expected.Size)
+			}
+		})
+	}
+}
@@ -0,0 +1,12 @@
+package a
+
+import (
+	"github.com/flowdev/spaghetti-cutter/fan/testdata/fan-proj/c"
+	"github.com/flowdev/spaghetti-cutter/x/config"
+)
+
+// A is just here to be called.
+func A() {
+	_ = config.File
+	c.C()
+}
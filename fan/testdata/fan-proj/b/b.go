@@ -0,0 +1,8 @@
+package b
+
+import "github.com/flowdev/spaghetti-cutter/fan/testdata/fan-proj/c"
+
+// B is just here to be called.
+func B() {
+	c.C()
+}
@@ -0,0 +1,8 @@
+package c
+
+import "log"
+
+// C is just here to be called.
+func C() {
+	log.Printf("INFO - c.C")
+}
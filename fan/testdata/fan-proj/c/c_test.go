@@ -0,0 +1,11 @@
+package c_test
+
+import (
+	"testing"
+
+	"github.com/flowdev/spaghetti-cutter/fan/testdata/fan-proj/c"
+)
+
+func TestC(t *testing.T) {
+	c.C()
+}
@@ -1,4 +1,4 @@
-module github.com/flowdev/spaghetti-cutter/size/testdata/tstexpr
+module github.com/flowdev/spaghetti-cutter/fan/testdata/fan-proj
 
 go 1.14
 
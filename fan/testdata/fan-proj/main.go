@@ -0,0 +1,17 @@
+package main
+
+import (
+	"github.com/flowdev/spaghetti-cutter/fan/testdata/fan-proj/a"
+	"github.com/flowdev/spaghetti-cutter/fan/testdata/fan-proj/b"
+	"github.com/flowdev/spaghetti-cutter/fan/testdata/fan-proj/c"
+	"github.com/flowdev/spaghetti-cutter/x/config"
+	"github.com/flowdev/spaghetti-cutter/x/dirs"
+)
+
+func main() {
+	_ = config.File
+	_ = dirs.FindRoot
+	a.A()
+	b.B()
+	c.C()
+}
@@ -2,16 +2,28 @@ package main
 
 import (
 	"flag"
+	"fmt"
+	"go/build"
 	"io/ioutil"
 	"log"
 	"os"
+	"path"
 	"path/filepath"
+	"strings"
+	"time"
 
+	"github.com/flowdev/spaghetti-cutter/api"
+	"github.com/flowdev/spaghetti-cutter/baseline"
+	"github.com/flowdev/spaghetti-cutter/calls"
+	"github.com/flowdev/spaghetti-cutter/component"
 	"github.com/flowdev/spaghetti-cutter/config"
+	"github.com/flowdev/spaghetti-cutter/data"
 	"github.com/flowdev/spaghetti-cutter/deps"
+	"github.com/flowdev/spaghetti-cutter/fan"
 	"github.com/flowdev/spaghetti-cutter/parse"
 	"github.com/flowdev/spaghetti-cutter/size"
 	"github.com/flowdev/spaghetti-cutter/x/dirs"
+	"github.com/flowdev/spaghetti-cutter/x/git"
 	"github.com/flowdev/spaghetti-cutter/x/pkgs"
 )
 
@@ -29,14 +41,23 @@ func cut(args []string) int {
 		usageRoot    = "root directory of the project"
 		defaultNoErr = false
 		usageNoErr   = "don't report errors and don't exit with an error"
+		usageSince   = "only report errors of packages changed since this git revision (e.g. origin/main)"
+		usageWriteBl = "write all current errors to the baseline file " + baseline.File
 	)
-	var startDir string
-	var noErr bool
+	var startDir, since string
+	var noErr, writeBl bool
 	fs := flag.NewFlagSet("spaghetti-cutter", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage of %s [options] [package patterns]:\n", fs.Name())
+		fmt.Fprintf(fs.Output(), "   or: %s [options] config show\n", fs.Name())
+		fs.PrintDefaults()
+	}
 	fs.StringVar(&startDir, "root", defaultRoot, usageRoot)
 	fs.StringVar(&startDir, "r", defaultRoot, usageRoot+usageShort)
 	fs.BoolVar(&noErr, "noerror", defaultNoErr, usageNoErr)
 	fs.BoolVar(&noErr, "e", defaultNoErr, usageNoErr+usageShort)
+	fs.StringVar(&since, "since", "", usageSince)
+	fs.BoolVar(&writeBl, "write-baseline", false, usageWriteBl)
 	err := fs.Parse(args)
 	if err != nil {
 		log.Printf("FATAL - %v", err)
@@ -54,6 +75,13 @@ func cut(args []string) int {
 		log.Printf("FATAL - unable to read configuration file %q: %v", cfgFile, err)
 		return 4
 	}
+	if fs.NArg() > 0 && fs.Arg(0) == "config" {
+		return configCmd(fs.Args()[1:], cfgBytes, cfgFile)
+	}
+	if writeBl && (since != "" || fs.NArg() > 0) {
+		log.Printf("FATAL - the baseline file can only be written by a full analysis (without package patterns and --since)")
+		return 2
+	}
 	cfg, err := config.Parse(cfgBytes, cfgFile)
 	if err != nil {
 		log.Printf("FATAL - %v", err)
@@ -62,27 +90,120 @@ func cut(args []string) int {
 
 	log.Printf("INFO - configuration 'allowOnlyIn': %s", cfg.AllowOnlyIn)
 	log.Printf("INFO - configuration 'allowAdditionally': %s", cfg.AllowAdditionally)
+	log.Printf("INFO - configuration 'allowCallOnlyIn': %s", cfg.AllowCallOnlyIn)
+	log.Printf("INFO - configuration 'deny': %s", cfg.Deny)
+	log.Printf("INFO - configuration 'denyTransitive': %s", cfg.DenyTransitive)
+	log.Printf("INFO - configuration 'facades': %s", cfg.Facades)
+	log.Printf("INFO - configuration 'externalAllowed': %s", externalAllowedString(cfg.ExternalAllowed))
+	log.Printf("INFO - configuration 'exclude': %s", cfg.Exclude)
 	log.Printf("INFO - configuration 'god': %s", cfg.God)
 	log.Printf("INFO - configuration 'tool': %s", cfg.Tool)
 	log.Printf("INFO - configuration 'db': %s", cfg.DB)
+	log.Printf("INFO - configuration 'generated': %s", cfg.Generated)
+	log.Printf("INFO - configuration 'testOnly': %s", cfg.TestOnly)
+	log.Printf("INFO - configuration 'tests': %s", cfg.Tests)
+	log.Printf("INFO - configuration 'types' (including built-in types): %s", cfg.AllTypeDefs())
+	log.Printf("INFO - configuration 'layers': %s", cfg.Layers)
+	log.Printf("INFO - configuration 'strictLayers': %t", cfg.StrictLayers)
+	log.Printf("INFO - configuration 'components': %s", cfg.Components)
+	log.Printf("INFO - configuration 'fanLimits': %s", cfg.FanLimits)
 	log.Printf("INFO - configuration 'size': %d", cfg.Size)
+	log.Printf("INFO - configuration 'sizeWeights': %s", cfg.SizeWeights)
+	log.Printf("INFO - configuration 'concurrency': %s", cfg.Concurrency)
+	log.Printf("INFO - configuration 'purity': %s", cfg.Purity)
 	log.Printf("INFO - configuration 'noGod': %t", cfg.NoGod)
+	log.Printf("INFO - configuration 'generatedFiles': size: %s, deps: %s",
+		cfg.GeneratedFiles.Size, cfg.GeneratedFiles.Deps)
 	log.Printf("INFO - no errors are reported: %t", noErr)
 
-	packs, err := parse.DirTree(root)
+	patterns, err := absPatterns(fs.Args())
+	if err != nil {
+		log.Printf("FATAL - %v", err)
+		return 2
+	}
+	if len(patterns) > 0 {
+		log.Printf("INFO - only analyzing packages: %q", patterns)
+	}
+
+	packs, rootPkg, err := parse.DirTree(root, cfg.Exclude, patterns...)
 	if err != nil {
 		log.Printf("FATAL - %v", err)
 		return 6
 	}
 
-	rootPkg := parse.RootPkg(packs)
 	log.Printf("INFO - root package: %s", rootPkg)
+	if cfg.AllowCallOnlyIn != nil || cfg.ChecksAPI() {
+		if err = parse.TypeCheck(root, packs); err != nil {
+			log.Printf("FATAL - %v", err)
+			return 6
+		}
+	}
 	pkgInfos := pkgs.UniquePackages(packs)
+	pkgs.LinkPackages(pkgInfos, rootPkg)
+
+	var modules map[string]string
+	if cfg.ExternalAllowed != nil {
+		if modules, err = parse.Modules(root); err != nil {
+			log.Printf("FATAL - %v", err)
+			return 6
+		}
+	}
+
+	var scope map[string]bool
+	if since != "" {
+		if scope, err = changedPackages(root, since, cfg, pkgInfos, rootPkg); err != nil {
+			log.Printf("FATAL - %v", err)
+			return 7
+		}
+		log.Printf("INFO - only reporting errors of packages in %d directories changed since %q", len(scope), since)
+	}
 
 	var errs []error
 	for _, pkgInfo := range pkgInfos {
-		errs = addErrors(errs, deps.Check(pkgInfo.Pkg, rootPkg, cfg))
-		errs = addErrors(errs, size.Check(pkgInfo.Pkg, rootPkg, cfg.Size))
+		if layer := deps.Layer(pkgInfo.Pkg, rootPkg, cfg); layer != "" {
+			log.Printf("INFO - layer of package '%s': %s", scopeName(pkgInfo.Pkg, rootPkg), layer)
+		}
+		var pkgErrs []error
+		pkgErrs = addErrors(pkgErrs, deps.Check(pkgInfo.Pkg, rootPkg, modules, cfg))
+		pkgErrs = addErrors(pkgErrs, deps.CheckTests(pkgInfo, rootPkg, cfg))
+		pkgErrs = addErrors(pkgErrs, calls.Check(pkgInfo.Pkg, rootPkg, cfg))
+		pkgErrs = addErrors(pkgErrs, api.Check(pkgInfo.Pkg, rootPkg, cfg))
+		pkgErrs = addErrors(pkgErrs, size.Check(pkgInfo.Pkg, rootPkg, cfg))
+		pkgErrs = addErrors(pkgErrs, fan.Check(pkgInfo, rootPkg, cfg))
+		if scope == nil || scope[scopeName(pkgInfo.Pkg, rootPkg)] {
+			errs = addErrors(errs, pkgErrs)
+		}
+	}
+	errs = addErrors(errs, component.Check(pkgInfos, rootPkg, scope, cfg))
+	now := time.Now()
+	errs = addErrors(errs, expiredInScope(cfg.AllowOnlyIn, "allowOnlyIn", now, scope))
+	errs = addErrors(errs, expiredInScope(cfg.AllowAdditionally, "allowAdditionally", now, scope))
+
+	blFile := filepath.Join(root, baseline.File)
+	if writeBl {
+		if err = baseline.New(errs).Write(blFile); err != nil {
+			log.Printf("FATAL - %v", err)
+			return 8
+		}
+		log.Printf("INFO - wrote %d errors to the baseline file %q", len(errs), blFile)
+		return 0
+	}
+	bl, err := baseline.Read(blFile)
+	if err != nil {
+		log.Printf("FATAL - %v", err)
+		return 8
+	}
+	if len(bl.Violations) > 0 {
+		var fixed []baseline.Entry
+		n := len(errs)
+		errs, fixed = bl.Subtract(errs)
+		log.Printf("INFO - %d known errors from the baseline file are ignored", n-len(errs))
+		if scope == nil && len(patterns) == 0 { // only a full analysis finds all errors
+			for _, e := range fixed {
+				log.Printf("WARNING - error from the baseline file occurs %d time(s) less (please update the baseline): %s",
+					e.Count, e.Fingerprint)
+			}
+		}
 	}
 
 	retCode := 0
@@ -100,6 +221,157 @@ func cut(args []string) int {
 	return retCode
 }
 
+// configCmd executes the config sub-command.
+// Currently only `config show` is supported that prints the configuration
+// with its preset expanded.
+func configCmd(args []string, cfgBytes []byte, cfgFile string) int {
+	if len(args) != 1 || args[0] != "show" {
+		log.Printf("FATAL - unknown config command %q (only 'config show' is supported)", args)
+		return 2
+	}
+	if _, err := config.Parse(cfgBytes, cfgFile); err != nil {
+		log.Printf("FATAL - %v", err)
+		return 5
+	}
+	out, err := config.Show(cfgBytes, cfgFile)
+	if err != nil {
+		log.Printf("FATAL - %v", err)
+		return 5
+	}
+	fmt.Println(string(out))
+	return 0
+}
+
+// absPatterns makes relative package patterns (like `./pkg/shopping/...`)
+// absolute, so they are independent of the root directory of the project.
+func absPatterns(patterns []string) ([]string, error) {
+	absPats := make([]string, len(patterns))
+	for i, p := range patterns {
+		if !build.IsLocalImport(p) {
+			absPats[i] = p
+			continue
+		}
+		absPat, err := filepath.Abs(p)
+		if err != nil {
+			return nil, fmt.Errorf("unable to find absolute path for package pattern %q: %w", p, err)
+		}
+		absPats[i] = absPat
+	}
+	return absPats, nil
+}
+
+// changedPackages returns the scope names of all packages that contain Go
+// files that changed since the given git revision.
+// If the configuration changed, too, all packages whose classification
+// (type or layer) changed are added.
+func changedPackages(root, rev string, cfg config.Config, pkgInfos map[string]*pkgs.PackageInfo, rootPkg string,
+) (map[string]bool, error) {
+	files, err := git.ChangedFiles(root, rev)
+	if err != nil {
+		return nil, err
+	}
+
+	scope := make(map[string]bool, len(files))
+	cfgChanged := false
+	for _, file := range files {
+		if file == config.File {
+			cfgChanged = true
+			continue
+		}
+		if path.Ext(file) != ".go" {
+			continue
+		}
+		dir := path.Dir(file)
+		if dir == "." {
+			dir = "/"
+		}
+		scope[dir] = true
+	}
+
+	if !cfgChanged {
+		return scope, nil
+	}
+	oldCfgBytes, err := git.FileAt(root, rev, config.File)
+	if err != nil {
+		log.Printf("WARNING - treating all packages as changed: %v", err)
+		oldCfgBytes = []byte(`{}`)
+	}
+	oldCfg, err := config.Parse(oldCfgBytes, rev+":"+config.File)
+	if err != nil {
+		return nil, err
+	}
+	for _, pkgInfo := range pkgInfos {
+		oldType := deps.Type(pkgInfo.Pkg, rootPkg, oldCfg)
+		newType := deps.Type(pkgInfo.Pkg, rootPkg, cfg)
+		if oldType != newType {
+			name := scopeName(pkgInfo.Pkg, rootPkg)
+			log.Printf("INFO - classification of package '%s' changed from %s to %s", name, oldType, newType)
+			scope[name] = true
+		}
+		oldLayer := deps.Layer(pkgInfo.Pkg, rootPkg, oldCfg)
+		newLayer := deps.Layer(pkgInfo.Pkg, rootPkg, cfg)
+		if oldLayer != newLayer {
+			name := scopeName(pkgInfo.Pkg, rootPkg)
+			log.Printf("INFO - layer of package '%s' changed from %q to %q", name, oldLayer, newLayer)
+			scope[name] = true
+		}
+	}
+	return scope, nil
+}
+
+// scopeName returns the directory of the package relative to the root.
+// So test packages have got the same scope name as the package they test.
+func scopeName(pkg *pkgs.Package, rootPkg string) string {
+	return strings.TrimSuffix(pkgs.UniquePackageName(pkgs.RelativePackageName(pkg, rootPkg)), "_test")
+}
+
+// expiredInScope returns the expired entries of the pattern map that concern
+// a package in scope (the package matches the key or the value of the entry).
+// A nil scope contains all packages.
+func expiredInScope(pm *data.PatternMap, key string, now time.Time, scope map[string]bool) []error {
+	errs := pm.Expired(key, now)
+	if scope == nil {
+		return errs
+	}
+	var scoped []error
+	for _, err := range errs {
+		v := err.(*data.Violation)
+		group := (*pm)[v.Pkg]
+		patterns := []data.Pattern{group.Left}
+		for _, right := range group.Right {
+			if right.Pattern == v.Target {
+				patterns = append(patterns, right)
+			}
+		}
+		if anyPackageMatches(patterns, scope) {
+			scoped = append(scoped, err)
+		}
+	}
+	return scoped
+}
+
+// anyPackageMatches returns true if one of the packages is matched fully by
+// one of the patterns. Variables in the patterns match anything.
+func anyPackageMatches(patterns []data.Pattern, names map[string]bool) bool {
+	for pkg := range names {
+		for _, p := range patterns {
+			if m := p.Regexp.FindString(pkg); m != "" && len(m) == len(pkg) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func addErrors(errs []error, newErrs []error) []error {
 	return append(errs, newErrs...)
 }
+
+// externalAllowedString distinguishes between no restriction at all and
+// allowing no external packages.
+func externalAllowedString(pl data.PatternList) string {
+	if pl == nil {
+		return "all external packages are allowed"
+	}
+	return pl.String()
+}
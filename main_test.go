@@ -1,11 +1,18 @@
 package main
 
 import (
+	"bytes"
+	"io"
 	"io/ioutil"
+	"log"
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/flowdev/spaghetti-cutter/baseline"
 	"github.com/flowdev/spaghetti-cutter/config"
+	"github.com/flowdev/spaghetti-cutter/testutil"
 )
 
 func TestCut(t *testing.T) {
@@ -13,7 +20,15 @@ func TestCut(t *testing.T) {
 		name               string
 		givenRoot          string
 		givenConfig        string
+		givenPatterns      []string
+		givenSince         string
+		givenNewFile       string // new (untracked) file relative to the root with the content "package <dir>"
+		givenBaseline      bool
+		givenWriteBaseline bool
+		givenCommand       []string
 		expectedReturnCode int
+		expectedOutput     string // part of the output, if given
+		unexpectedOutput   string // part that the output mustn't contain, if given
 	}{
 		{
 			name:               "no-config-good-proj",
@@ -38,6 +53,88 @@ func TestCut(t *testing.T) {
 						"size": 1024
 					}`,
 			expectedReturnCode: 0,
+		}, {
+			name:      "strict-config-good-proj-only-tools",
+			givenRoot: "good-proj",
+			givenConfig: `{
+						"tool": ["pkg/x/*"], "db": ["pkg/db/*"],
+						"size": 16
+					}`,
+			givenPatterns:      []string{"./testdata/good-proj/pkg/x/..."},
+			expectedReturnCode: 0,
+		}, {
+			name:      "strict-config-good-proj-nothing-changed",
+			givenRoot: "good-proj",
+			givenConfig: `{
+						"tool": ["pkg/x/*"], "db": ["pkg/db/*"],
+						"size": 16
+					}`,
+			givenSince:         "HEAD",
+			expectedReturnCode: 0,
+		}, {
+			name:      "strict-config-good-proj-new-file",
+			givenRoot: "good-proj",
+			givenConfig: `{
+						"tool": ["pkg/x/*"], "db": ["pkg/db/*"],
+						"allowAdditionally": {"pkg/domain2": [{"pattern": "pkg/domain3", "expires": "2000-01-01"}]},
+						"components": [
+							{"name": "domain1", "patterns": ["pkg/domain1"], "mayImport": []},
+							{"name": "domain2", "patterns": ["pkg/domain2"], "mayImport": []},
+							{"name": "store", "patterns": ["pkg/db/**"]}
+						],
+						"size": 16
+					}`,
+			givenSince:         "HEAD",
+			givenNewFile:       "pkg/domain1/new.go",
+			expectedReturnCode: 1,
+			expectedOutput:     "component 'domain1' isn't allowed to use component 'store'",
+			unexpectedOutput:   "domain2",
+		}, {
+			name:      "strict-config-good-proj-baseline",
+			givenRoot: "good-proj",
+			givenConfig: `{
+						"tool": ["pkg/x/*"], "db": ["pkg/db/*"],
+						"size": 16
+					}`,
+			givenBaseline:      true,
+			expectedReturnCode: 0,
+		}, {
+			name:               "write-baseline-with-patterns-good-proj",
+			givenRoot:          "good-proj",
+			givenConfig:        `{"tool": ["pkg/x/*"], "db": ["pkg/db/*"], "size": 16}`,
+			givenPatterns:      []string{"./testdata/good-proj/pkg/x/..."},
+			givenWriteBaseline: true,
+			expectedReturnCode: 2,
+			expectedOutput:     "the baseline file can only be written by a full analysis",
+		}, {
+			name:               "write-baseline-since-good-proj",
+			givenRoot:          "good-proj",
+			givenConfig:        `{"tool": ["pkg/x/*"], "db": ["pkg/db/*"], "size": 16}`,
+			givenSince:         "HEAD",
+			givenWriteBaseline: true,
+			expectedReturnCode: 2,
+			expectedOutput:     "the baseline file can only be written by a full analysis",
+		}, {
+			name:      "preset-config-good-proj",
+			givenRoot: "good-proj",
+			givenConfig: `{
+						"preset": "web-api",
+						"allowAdditionally": {"pkg/domain4": ["pkg/domain3"], "pkg/db/store": ["pkg/db/model"]},
+						"size": 1024
+					}`,
+			expectedReturnCode: 0,
+		}, {
+			name:               "config-show-good-proj",
+			givenRoot:          "good-proj",
+			givenConfig:        `{"preset": "hexagonal", "size": 1024}`,
+			givenCommand:       []string{"config", "show"},
+			expectedReturnCode: 0,
+		}, {
+			name:               "bad-config-command-good-proj",
+			givenRoot:          "good-proj",
+			givenConfig:        `{}`,
+			givenCommand:       []string{"config", "hide"},
+			expectedReturnCode: 2,
 			/*
 				}, {
 					name:               "no-config-bad-proj",
@@ -50,24 +147,55 @@ func TestCut(t *testing.T) {
 
 	for _, spec := range specs {
 		t.Run(spec.name, func(t *testing.T) {
-			root := mustAbs(filepath.Join("testdata", spec.givenRoot))
+			root := testutil.MustAbs(filepath.Join("testdata", spec.givenRoot))
 			mustWriteFile(filepath.Join(root, config.File), []byte(spec.givenConfig))
+			if spec.givenNewFile != "" {
+				file := filepath.Join(root, spec.givenNewFile)
+				defer os.Remove(file)
+				mustWriteFile(file, []byte("package "+filepath.Base(filepath.Dir(file))+"\n"))
+			}
+			if spec.givenBaseline {
+				blFile := filepath.Join(root, baseline.File)
+				defer os.Remove(blFile)
+				if rc := cut([]string{"--root", root, "--write-baseline"}); rc != 0 {
+					t.Fatalf("Expected return code 0 for writing the baseline but got: %d", rc)
+				}
+			}
 			args := []string{"--root", root}
+			if spec.givenWriteBaseline {
+				args = append(args, "--write-baseline")
+			}
+			if spec.givenSince != "" {
+				args = append(args, "--since", spec.givenSince)
+			}
+			args = append(args, spec.givenPatterns...)
+			args = append(args, spec.givenCommand...)
+			var output bytes.Buffer
+			log.SetOutput(io.MultiWriter(os.Stderr, &output))
+			defer log.SetOutput(os.Stderr)
 			actualReturnCode := cut(args)
 
 			if actualReturnCode != spec.expectedReturnCode {
 				t.Errorf("Expected return code %d but got: %d", spec.expectedReturnCode, actualReturnCode)
 			}
+			if spec.expectedOutput != "" && !strings.Contains(output.String(), spec.expectedOutput) {
+				t.Errorf("Expected output to contain %q", spec.expectedOutput)
+			}
+			if spec.unexpectedOutput != "" && containsErrorWith(output.String(), spec.unexpectedOutput) {
+				t.Errorf("Expected no error containing %q", spec.unexpectedOutput)
+			}
 		})
 	}
 }
 
-func mustAbs(path string) string {
-	absPath, err := filepath.Abs(path)
-	if err != nil {
-		panic(err.Error())
+// containsErrorWith returns true if an error line of the output contains s.
+func containsErrorWith(output, s string) bool {
+	for _, line := range strings.Split(output, "\n") {
+		if strings.Contains(line, "ERROR - ") && strings.Contains(line, s) {
+			return true
+		}
 	}
-	return absPath
+	return false
 }
 
 func mustWriteFile(filename string, data []byte) {
@@ -0,0 +1,48 @@
+package parse
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Modules returns the module paths of all packages that the packages of the
+// directory tree starting at root depend on (including tests).
+// The map is keyed by package path. Packages that don't belong to a module
+// (e.g. of the standard library) aren't contained.
+//
+// The vendored version of golang.org/x/tools doesn't export the module of a
+// package, so `go list` is used directly.
+func Modules(root string) (map[string]string, error) {
+	return goList(root, "-test", "-f", "{{.ImportPath}} {{with .Module}}{{.Path}}{{end}}")
+}
+
+// goList lists all packages of the directory tree starting at root and their
+// dependencies.
+// The format has to print the import path followed by a single value.
+// Packages without value (or test variants like `pkg [pkg.test]`) aren't
+// contained in the returned map.
+func goList(root string, args ...string) (map[string]string, error) {
+	var stderr bytes.Buffer
+	args = append(append([]string{"list", "-e", "-deps"}, args...), root+"/...")
+	cmd := exec.Command("go", args...)
+	cmd.Dir = root
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("unable to run 'go %s' in directory %q: %w: %s",
+			strings.Join(args, " "), root, err, strings.TrimSpace(stderr.String()))
+	}
+
+	values := make(map[string]string)
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		values[fields[0]] = fields[1]
+	}
+	return values, nil
+}
@@ -2,30 +2,99 @@ package parse
 
 import (
 	"errors"
+	"go/token"
 	"strings"
 
+	"github.com/flowdev/spaghetti-cutter/data"
 	"github.com/flowdev/spaghetti-cutter/x/pkgs"
 	"golang.org/x/tools/go/packages"
 )
 
 // DirTree is parsing the whole directory tree starting at root
 // looking for Go packages and analyzing them.
-func DirTree(root string) ([]*pkgs.Package, error) {
+// Packages matching one of the exclude patterns aren't parsed at all.
+// If package patterns (like `./pkg/shopping/...`) are given, only the matching
+// packages are parsed.
+// The root package is always computed for the whole tree.
+func DirTree(root string, exclude data.PatternList, patterns ...string) ([]*pkgs.Package, string, error) {
+	rootPkg := ""
+
+	if len(exclude) > 0 || len(patterns) > 0 {
+		var err error
+		if patterns, rootPkg, err = includedPackages(root, exclude, patterns); err != nil {
+			return nil, "", err
+		}
+		if len(patterns) == 0 {
+			return nil, rootPkg, nil
+		}
+	} else {
+		patterns = []string{root + "/..."}
+	}
+
 	parseCfg := &packages.Config{
 		Logf:  nil, // log.Printf (for debug), nil (for release)
 		Dir:   root,
 		Tests: true,
 		Mode:  packages.NeedName | packages.NeedImports | packages.NeedSyntax,
+		Fset:  token.NewFileSet(),
 	}
 
-	packs, err := packages.Load(parseCfg, root+"/...")
+	packs, err := packages.Load(parseCfg, patterns...)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	if packages.PrintErrors(packs) > 0 {
-		return nil, errors.New("unable to parse packages at root: " + root)
+		return nil, "", errors.New("unable to parse packages at root: " + root)
+	}
+	for _, pkg := range packs {
+		pkg.Fset = parseCfg.Fset // positions in the syntax trees are needed for reporting
+	}
+	if rootPkg == "" {
+		rootPkg = RootPkg(packs)
+	}
+	return packs, rootPkg, nil
+}
+
+// includedPackages lists the packages of the directory tree without parsing
+// them and returns the paths of the packages that match the given patterns and
+// aren't excluded.
+func includedPackages(root string, exclude data.PatternList, patterns []string) ([]string, string, error) {
+	listCfg := &packages.Config{
+		Logf: nil, // log.Printf (for debug), nil (for release)
+		Dir:  root,
+		Mode: packages.NeedName,
+	}
+
+	packs, err := packages.Load(listCfg, root+"/...")
+	if err != nil {
+		return nil, "", err
+	}
+	rootPkg := RootPkg(packs)
+
+	if len(patterns) > 0 {
+		if packs, err = packages.Load(listCfg, patterns...); err != nil {
+			return nil, "", err
+		}
+	}
+
+	paths := make([]string, 0, len(packs))
+	for _, pkg := range packs {
+		if !isExcluded(pkg, rootPkg, exclude) {
+			paths = append(paths, pkg.PkgPath)
+		}
+	}
+	return paths, rootPkg, nil
+}
+
+func isExcluded(pkg *pkgs.Package, rootPkg string, exclude data.PatternList) bool {
+	relPkg, strictRelPkg := pkgs.RelativePackageName(pkg, rootPkg)
+	if strictRelPkg != "" {
+		if _, full := exclude.MatchString(strictRelPkg, nil); full {
+			return true
+		}
 	}
-	return packs, nil
+	_, full := exclude.MatchString(relPkg, nil)
+	return full
 }
 
 // RootPkg returns the package path of the root package of all the given
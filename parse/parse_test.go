@@ -6,19 +6,26 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/flowdev/spaghetti-cutter/data"
 	"github.com/flowdev/spaghetti-cutter/parse"
+	"github.com/flowdev/spaghetti-cutter/testutil"
 	"github.com/flowdev/spaghetti-cutter/x/pkgs"
 )
 
 func TestDirTree(t *testing.T) {
 	specs := []struct {
-		name          string
-		expectedError bool
-		expectedPkgs  string
+		name            string
+		givenRoot       string
+		givenExclude    []string
+		givenPatterns   []string
+		expectedError   bool
+		expectedPkgs    string
+		expectedRootPkg string
 	}{
 		{
-			name:          "happy-path",
-			expectedError: false,
+			name:            "happy-path",
+			expectedError:   false,
+			expectedRootPkg: "github.com/flowdev/spaghetti-cutter/parse/testdata/happy-path",
 			expectedPkgs: "alltst: github.com/flowdev/spaghetti-cutter/parse/testdata/happy-path/alltst | " +
 				"alltst: github.com/flowdev/spaghetti-cutter/parse/testdata/happy-path/alltst [T] | " +
 				"alltst_test: github.com/flowdev/spaghetti-cutter/parse/testdata/happy-path/alltst_test [T] | " +
@@ -30,6 +37,28 @@ func TestDirTree(t *testing.T) {
 				"main: github.com/flowdev/spaghetti-cutter/parse/testdata/happy-path/unittst.test [T] | " +
 				"unittst: github.com/flowdev/spaghetti-cutter/parse/testdata/happy-path/unittst | " +
 				"unittst: github.com/flowdev/spaghetti-cutter/parse/testdata/happy-path/unittst [T]",
+		}, {
+			name:            "happy-path-excluding",
+			givenRoot:       "happy-path",
+			givenExclude:    []string{"/", "a*"},
+			expectedError:   false,
+			expectedRootPkg: "github.com/flowdev/spaghetti-cutter/parse/testdata/happy-path",
+			expectedPkgs: "main: github.com/flowdev/spaghetti-cutter/parse/testdata/happy-path/unittst.test [T] | " +
+				"unittst: github.com/flowdev/spaghetti-cutter/parse/testdata/happy-path/unittst | " +
+				"unittst: github.com/flowdev/spaghetti-cutter/parse/testdata/happy-path/unittst [T]",
+		}, {
+			name:            "happy-path-patterns",
+			givenRoot:       "happy-path",
+			givenPatterns:   []string{"./unittst", "./a..."},
+			givenExclude:    []string{"alltst"},
+			expectedError:   false,
+			expectedRootPkg: "github.com/flowdev/spaghetti-cutter/parse/testdata/happy-path",
+			expectedPkgs: "apitst: github.com/flowdev/spaghetti-cutter/parse/testdata/happy-path/apitst | " +
+				"apitst_test: github.com/flowdev/spaghetti-cutter/parse/testdata/happy-path/apitst_test [T] | " +
+				"main: github.com/flowdev/spaghetti-cutter/parse/testdata/happy-path/apitst.test [T] | " +
+				"main: github.com/flowdev/spaghetti-cutter/parse/testdata/happy-path/unittst.test [T] | " +
+				"unittst: github.com/flowdev/spaghetti-cutter/parse/testdata/happy-path/unittst | " +
+				"unittst: github.com/flowdev/spaghetti-cutter/parse/testdata/happy-path/unittst [T]",
 		}, {
 			name:          "error-path",
 			expectedError: true,
@@ -39,7 +68,15 @@ func TestDirTree(t *testing.T) {
 
 	for _, spec := range specs {
 		t.Run(spec.name, func(t *testing.T) {
-			actualPkgs, err := parse.DirTree(mustAbs(filepath.Join("testdata", spec.name)))
+			root := spec.givenRoot
+			if root == "" {
+				root = spec.name
+			}
+			exclude, err := data.NewSimplePatternList(spec.givenExclude, "exclude")
+			if err != nil {
+				t.Fatalf("received UNexpected error: %v", err)
+			}
+			actualPkgs, actualRootPkg, err := parse.DirTree(testutil.MustAbs(filepath.Join("testdata", root)), exclude, spec.givenPatterns...)
 			//t.Logf("err: %v, actualPkgs: %#v", err, actualPkgs)
 			if spec.expectedError {
 				if err != nil {
@@ -50,6 +87,9 @@ func TestDirTree(t *testing.T) {
 			} else if err != nil {
 				t.Fatalf("received UNexpected error: %v", err)
 			}
+			if actualRootPkg != spec.expectedRootPkg {
+				t.Errorf("expected root package %q, actual %q", spec.expectedRootPkg, actualRootPkg)
+			}
 			actualPkgsString := packagesAsString(actualPkgs)
 			if actualPkgsString != spec.expectedPkgs {
 				t.Errorf("expected parsed packages %q, actual %q (len=%d)",
@@ -119,14 +159,6 @@ func pkgsForPaths(paths []string) []*pkgs.Package {
 	return packs
 }
 
-func mustAbs(path string) string {
-	absPath, err := filepath.Abs(path)
-	if err != nil {
-		panic(err.Error())
-	}
-	return absPath
-}
-
 func isTestPkg(pkg *pkgs.Package) bool {
 	return strings.HasSuffix(pkg.PkgPath, "_test") ||
 		strings.HasSuffix(pkg.PkgPath, ".test") ||
@@ -0,0 +1,62 @@
+package parse
+
+import (
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+
+	"github.com/flowdev/spaghetti-cutter/x/pkgs"
+)
+
+// TypeCheck computes the type information (Types and TypesInfo) of the given
+// packages from their syntax trees.
+// Imported packages are read from the export data of the build cache.
+// Test packages are skipped.
+//
+// The vendored version of golang.org/x/tools can't load type information
+// with current Go versions, so it is done here.
+func TypeCheck(root string, packs []*pkgs.Package) error {
+	exports, err := goList(root, "-export", "-f", "{{.ImportPath}} {{.Export}}")
+	if err != nil {
+		return err
+	}
+
+	imp := importer.ForCompiler(token.NewFileSet(), "gc", func(path string) (io.ReadCloser, error) {
+		file, ok := exports[path]
+		if !ok {
+			return nil, fmt.Errorf("no export data for package %q", path)
+		}
+		return os.Open(file)
+	})
+
+	for _, pkg := range packs {
+		if pkgs.IsTestPackage(pkg) || pkg.Types != nil {
+			continue
+		}
+		var typeErr error
+		conf := types.Config{
+			Importer:    imp,
+			FakeImportC: true,
+			Error: func(err error) {
+				if typeErr == nil {
+					typeErr = err
+				}
+			},
+		}
+		info := &types.Info{
+			Types: make(map[ast.Expr]types.TypeAndValue),
+			Defs:  make(map[*ast.Ident]types.Object),
+			Uses:  make(map[*ast.Ident]types.Object),
+		}
+		pkg.Types, _ = conf.Check(pkg.PkgPath, pkg.Fset, pkg.Syntax, info)
+		pkg.TypesInfo = info
+		if typeErr != nil {
+			return fmt.Errorf("unable to type check package %q: %w", pkg.PkgPath, typeErr)
+		}
+	}
+	return nil
+}
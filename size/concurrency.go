@@ -0,0 +1,86 @@
+package size
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+
+	"github.com/flowdev/spaghetti-cutter/config"
+	"github.com/flowdev/spaghetti-cutter/data"
+	"github.com/flowdev/spaghetti-cutter/x/pkgs"
+)
+
+// concurrency records the usages of concurrency in a package that isn't
+// allowed to use it.
+type concurrency struct {
+	fset     *token.FileSet
+	uniqPkg  string
+	channels bool // select and send statements, receive operations and channel types count, too
+	because  string
+	errs     []error
+}
+
+func newConcurrency(pkg *pkgs.Package, uniqPkg string, c config.Concurrency) *concurrency {
+	return &concurrency{
+		fset:     pkg.Fset,
+		uniqPkg:  uniqPkg,
+		channels: c.Channels,
+		because:  data.Because("concurrency", "allowedIn "+c.AllowedIn.String(), c.Location, ""),
+	}
+}
+
+// visit is called for every node whose size is computed (see:
+// astsize.Visitor) and records its usage of concurrency.
+func (c *concurrency) visit(n ast.Node, fun ast.Node) {
+	what := concurrencyUsage(n, c.channels)
+	if what == "" {
+		return
+	}
+	target := what
+	if fd, ok := fun.(*ast.FuncDecl); ok {
+		target += " in " + pkgs.FuncName(fd)
+	}
+	c.errs = append(c.errs, &data.Violation{Check: "concurrency", Pkg: c.uniqPkg, Target: target, Msg: fmt.Sprintf(
+		"package '%s' isn't allowed to use a %s at %s%s", c.uniqPkg, what, c.fset.Position(n.Pos()), c.because)})
+}
+
+// concurrencyUsage returns the kind of concurrency that the node uses or an
+// empty string.
+// With channels select and send statements, receive operations and channel
+// types count, too.
+func concurrencyUsage(n ast.Node, channels bool) string {
+	what := ""
+	switch n := n.(type) {
+	case *ast.GoStmt:
+		return "go statement"
+	case *ast.SelectStmt:
+		what = "select statement"
+	case *ast.SendStmt:
+		what = "send statement"
+	case *ast.ChanType:
+		what = "channel type"
+	case *ast.UnaryExpr:
+		if n.Op == token.ARROW {
+			what = "receive operation"
+		}
+	}
+	if !channels {
+		return ""
+	}
+	return what
+}
+
+// isConcurrencyAllowed returns true if the package may use concurrency or
+// if concurrency isn't checked at all.
+func isConcurrencyAllowed(relPkg, strictRelPkg string, c config.Concurrency) bool {
+	if c.AllowedIn == nil {
+		return true
+	}
+	if strictRelPkg != "" {
+		if _, full := c.AllowedIn.MatchString(strictRelPkg, nil); full {
+			return true
+		}
+	}
+	_, full := c.AllowedIn.MatchString(relPkg, nil)
+	return full
+}
@@ -0,0 +1,111 @@
+package size
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"path/filepath"
+
+	"github.com/flowdev/spaghetti-cutter/config"
+	"github.com/flowdev/spaghetti-cutter/data"
+	"github.com/flowdev/spaghetti-cutter/x/pkgs"
+)
+
+// purity records the package-level variables and init functions of a
+// package that has to be pure.
+type purity struct {
+	fset        *token.FileSet
+	uniqPkg     string
+	allowedVars data.PatternList
+	because     string
+	errs        []error
+}
+
+func newPurity(pkg *pkgs.Package, uniqPkg string, p config.Purity) *purity {
+	return &purity{
+		fset:        pkg.Fset,
+		uniqPkg:     uniqPkg,
+		allowedVars: p.AllowedVars,
+		because:     data.Because("purity", "", p.Location, ""),
+	}
+}
+
+// visit is called for every node whose size is computed (see:
+// astsize.Visitor) and records the package-level variables and init
+// functions. Sentinel errors, blank variables and the allowed variables are
+// fine.
+func (pure *purity) visit(n ast.Node, fun ast.Node) {
+	if fun != nil { // only package-level declarations are of interest
+		return
+	}
+	switch d := n.(type) {
+	case *ast.FuncDecl:
+		if d.Recv == nil && d.Name.Name == "init" {
+			pos := pure.fset.Position(d.Pos()) // a package can have many init functions
+			pure.impure("an init function", "init in "+filepath.Base(pos.Filename), pos)
+		}
+	case *ast.GenDecl:
+		if d.Tok != token.VAR {
+			return
+		}
+		for _, spec := range d.Specs {
+			vs := spec.(*ast.ValueSpec)
+			if isSentinelError(vs) {
+				continue
+			}
+			for _, id := range vs.Names {
+				if _, full := pure.allowedVars.MatchString(id.Name, nil); full || id.Name == "_" {
+					continue
+				}
+				pure.impure(fmt.Sprintf("the package-level variable '%s'", id.Name), id.Name, pure.fset.Position(id.Pos()))
+			}
+		}
+	}
+}
+
+func (pure *purity) impure(what, target string, pos token.Position) {
+	pure.errs = append(pure.errs, &data.Violation{Check: "purity", Pkg: pure.uniqPkg, Target: target, Msg: fmt.Sprintf(
+		"package '%s' isn't allowed to have %s at %s%s", pure.uniqPkg, what, pos, pure.because)})
+}
+
+// isSentinelError returns true if all values of the spec are created with
+// `errors.New` or `fmt.Errorf`.
+func isSentinelError(spec *ast.ValueSpec) bool {
+	if len(spec.Values) == 0 {
+		return false
+	}
+	for _, v := range spec.Values {
+		call, ok := v.(*ast.CallExpr)
+		if !ok {
+			return false
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return false
+		}
+		pkg, ok := sel.X.(*ast.Ident)
+		if !ok {
+			return false
+		}
+		if !(pkg.Name == "errors" && sel.Sel.Name == "New") && !(pkg.Name == "fmt" && sel.Sel.Name == "Errorf") {
+			return false
+		}
+	}
+	return true
+}
+
+// isPure returns true if the package has to be pure.
+func isPure(relPkg, strictRelPkg string, cfg config.Config) bool {
+	for _, pkg := range []string{strictRelPkg, relPkg} {
+		if pkg == "" {
+			continue
+		}
+		if atAll, _ := cfg.Tool.MatchString(pkg, nil); atAll && cfg.Purity.Tool {
+			return true
+		}
+		if _, full := cfg.Purity.Patterns.MatchString(pkg, nil); full {
+			return true
+		}
+	}
+	return false
+}
@@ -6,38 +6,85 @@ import (
 	"go/ast"
 	"log"
 
+	"github.com/flowdev/spaghetti-cutter/config"
+	"github.com/flowdev/spaghetti-cutter/data"
+	"github.com/flowdev/spaghetti-cutter/x/astsize"
 	"github.com/flowdev/spaghetti-cutter/x/pkgs"
 )
 
 // Check checks the complexity of the given package and reports if it is too
 // big.
-func Check(pkg *pkgs.Package, rootPkg string, maxSize uint) []error {
+// Usages of concurrency in packages that aren't allowed to use it and
+// package-level variables and init functions of packages that have to be pure
+// are reported, too.
+func Check(pkg *pkgs.Package, rootPkg string, cfg config.Config) []error {
 	if pkgs.IsTestPackage(pkg) {
 		return nil
 	}
 	relPkg, strictRelPkg := pkgs.RelativePackageName(pkg, rootPkg)
 	uniqPkg := pkgs.UniquePackageName(relPkg, strictRelPkg)
+	var conc *concurrency
+	var pure *purity
+	var visitors []astsize.Visitor
+	if !isConcurrencyAllowed(relPkg, strictRelPkg, cfg.Concurrency) {
+		conc = newConcurrency(pkg, uniqPkg, cfg.Concurrency)
+		visitors = append(visitors, conc.visit)
+	}
+	if isPure(relPkg, strictRelPkg, cfg) {
+		pure = newPurity(pkg, uniqPkg, cfg.Purity)
+		visitors = append(visitors, pure.visit)
+	}
+	visit := combine(visitors)
 
-	var realSize uint
+	var realSize, genSize uint
 	for _, astf := range pkg.Syntax {
-		realSize += sizeOfFile(astf)
+		// ignored generated files are still visited for concurrency and purity
+		fileSize := cfg.SizeWeights.VisitFile(astf, visit)
+		if cfg.GeneratedFiles.Size != config.GeneratedNormal && pkgs.IsGeneratedFile(astf) {
+			if cfg.GeneratedFiles.Size == config.GeneratedSeparate {
+				genSize += fileSize
+			}
+			continue
+		}
+		realSize += fileSize
 	}
 	log.Printf("INFO - Size of package '%s': %d", uniqPkg, realSize)
 
-	if realSize > maxSize {
-		return []error{
-			fmt.Errorf("the maximum size for package '%s' is %d but it's real size is: %d",
-				uniqPkg, maxSize, realSize),
+	var errs []error
+	if conc != nil {
+		errs = conc.errs
+	}
+	if pure != nil {
+		errs = append(errs, pure.errs...)
+	}
+	if realSize > cfg.Size {
+		errs = append(errs, &data.Violation{Check: "size", Pkg: uniqPkg, Size: realSize, Msg: fmt.Sprintf(
+			"the maximum size for package '%s' is %d but it's real size is: %d",
+			uniqPkg, cfg.Size, realSize)})
+	}
+	if genSize > 0 {
+		log.Printf("INFO - Size of generated code in package '%s': %d", uniqPkg, genSize)
+		if genSize > cfg.Size {
+			errs = append(errs, &data.Violation{Check: "size-generated", Pkg: uniqPkg, Size: genSize, Msg: fmt.Sprintf(
+				"the maximum size for generated code in package '%s' is %d but it's real size is: %d",
+				uniqPkg, cfg.Size, genSize)})
 		}
 	}
-	return nil
+	return errs
 }
 
-func sizeOfFile(astf *ast.File) uint {
-	var size uint
-
-	for _, decl := range astf.Decls {
-		size += sizeOfDecl(decl)
+// combine returns a visitor that calls all the given visitors or nil if there
+// aren't any.
+func combine(visitors []astsize.Visitor) astsize.Visitor {
+	switch len(visitors) {
+	case 0:
+		return nil
+	case 1:
+		return visitors[0]
+	}
+	return func(n ast.Node, fun ast.Node) {
+		for _, visit := range visitors {
+			visit(n, fun)
+		}
 	}
-	return size
 }
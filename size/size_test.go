@@ -2,53 +2,156 @@ package size_test
 
 import (
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/flowdev/spaghetti-cutter/config"
 	"github.com/flowdev/spaghetti-cutter/parse"
 	"github.com/flowdev/spaghetti-cutter/size"
+	"github.com/flowdev/spaghetti-cutter/testutil"
 )
 
 func TestCheck(t *testing.T) {
 	specs := []struct {
-		name           string
-		givenMaxSize   uint
-		expectedErrors int
+		name            string
+		givenRoot       string
+		givenConfig     string
+		expectedErrors  int
+		expectedMessage string // part of one of the errors, if given
 	}{
 		{
 			name:           "normal-size-no-errors",
-			givenMaxSize:   1024,
+			givenConfig:    `{"size": 1024}`,
 			expectedErrors: 0,
 		}, {
 			name:           "medium-size-one-error",
-			givenMaxSize:   64,
+			givenConfig:    `{"size": 64}`,
 			expectedErrors: 1,
 		}, {
 			name:           "small-size-two-errors",
-			givenMaxSize:   32,
+			givenConfig:    `{"size": 32}`,
 			expectedErrors: 2,
 		}, {
 			name:           "tiny-size-many-errors",
-			givenMaxSize:   8,
+			givenConfig:    `{"size": 8}`,
 			expectedErrors: 7,
+		}, {
+			name:           "heavy-weights-medium-size-two-errors",
+			givenConfig:    `{"size": 64, "sizeWeights": {"ident": 2, "funcType": 8}}`,
+			expectedErrors: 2,
+		}, {
+			name:           "light-weights-tiny-size-one-error",
+			givenConfig:    `{"size": 16, "sizeWeights": {"ident": 0}}`,
+			expectedErrors: 1,
+		}, {
+			name:           "normal-generated-files-one-error",
+			givenRoot:      "generated",
+			givenConfig:    `{"size": 16}`,
+			expectedErrors: 1,
+		}, {
+			name:           "ignore-generated-files-no-errors",
+			givenRoot:      "generated",
+			givenConfig:    `{"size": 16, "generatedFiles": {"size": "ignore"}}`,
+			expectedErrors: 0,
+		}, {
+			name:           "separate-generated-files-one-error",
+			givenRoot:      "generated",
+			givenConfig:    `{"size": 16, "generatedFiles": {"size": "separate"}}`,
+			expectedErrors: 1,
+		}, {
+			name:           "unchecked-concurrency-no-errors",
+			givenRoot:      "concurrency",
+			givenConfig:    `{"size": 1024}`,
+			expectedErrors: 0,
+		}, {
+			name:            "allowed-concurrency-one-error",
+			givenRoot:       "concurrency",
+			givenConfig:     `{"size": 1024, "concurrency": {"allowedIn": ["worker"]}}`,
+			expectedErrors:  1,
+			expectedMessage: "package 'domain' isn't allowed to use a go statement at ",
+		}, {
+			name:           "allowed-concurrency-with-channels-six-errors",
+			givenRoot:      "concurrency",
+			givenConfig:    `{"size": 1024, "concurrency": {"allowedIn": ["worker"], "channels": true}}`,
+			expectedErrors: 6,
+			expectedMessage: "domain.go:14:2 (because of concurrency allowedIn `worker` " +
+				"in allowed-concurrency-with-channels-six-errors:1)",
+		}, {
+			name:            "allowed-concurrency-with-channels-receive-error",
+			givenRoot:       "concurrency",
+			givenConfig:     `{"size": 1024, "concurrency": {"allowedIn": ["worker"], "channels": true}}`,
+			expectedErrors:  6,
+			expectedMessage: "package 'domain' isn't allowed to use a receive operation at ",
+		}, {
+			name:           "no-concurrency-two-errors",
+			givenRoot:      "concurrency",
+			givenConfig:    `{"size": 1024, "concurrency": {}}`,
+			expectedErrors: 2,
+		}, {
+			name:            "ignored-generated-files-concurrency-three-errors",
+			givenRoot:       "generated-concurrency",
+			givenConfig:     `{"size": 1024, "generatedFiles": {"size": "ignore"}, "concurrency": {"allowedIn": ["worker"], "channels": true}}`,
+			expectedErrors:  3,
+			expectedMessage: "package 'gen' isn't allowed to use a go statement at ",
+		}, {
+			name:            "ignored-generated-files-purity-one-error",
+			givenRoot:       "generated-concurrency",
+			givenConfig:     `{"size": 1024, "generatedFiles": {"size": "ignore"}, "purity": {"patterns": ["gen"]}}`,
+			expectedErrors:  1,
+			expectedMessage: "package 'gen' isn't allowed to have the package-level variable 'workers' at ",
+		}, {
+			name:           "unchecked-purity-no-errors",
+			givenRoot:      "purity",
+			givenConfig:    `{"size": 1024, "tool": ["x/*"]}`,
+			expectedErrors: 0,
+		}, {
+			name:            "pure-tools-four-errors",
+			givenRoot:       "purity",
+			givenConfig:     `{"size": 1024, "tool": ["x/*"], "purity": {"tool": true}}`,
+			expectedErrors:  4,
+			expectedMessage: "package 'x/pure/sub' isn't allowed to have the package-level variable 'counter' at ",
+		}, {
+			name:            "pure-tools-with-allowed-vars-three-errors",
+			givenRoot:       "purity",
+			givenConfig:     `{"size": 1024, "tool": ["x/*"], "purity": {"tool": true, "allowedVars": ["Default*"]}}`,
+			expectedErrors:  3,
+			expectedMessage: "pure.go:18:1 (because of purity in pure-tools-with-allowed-vars-three-errors:1)",
+		}, {
+			name:            "pure-patterns-two-errors",
+			givenRoot:       "purity",
+			givenConfig:     `{"size": 1024, "tool": ["x/*"], "purity": {"patterns": ["domain"]}}`,
+			expectedErrors:  2,
+			expectedMessage: "package 'domain' isn't allowed to have an init function at ",
 		},
 	}
 
 	for _, spec := range specs {
 		t.Run(spec.name, func(t *testing.T) {
-			pkgs, err := parse.DirTree(mustAbs(filepath.Join("testdata", "size")))
+			cfg, err := config.Parse([]byte(spec.givenConfig), spec.name)
+			if err != nil {
+				t.Fatalf("got unexpected error: %v", err)
+			}
+
+			root := spec.givenRoot
+			if root == "" {
+				root = "size"
+			}
+			pkgs, rootPkg, err := parse.DirTree(testutil.MustAbs(filepath.Join("testdata", root)), nil)
 			if err != nil {
 				t.Fatalf("Fatal parse error: %v", err)
 			}
 
 			var errs []string
-			rootPkg := parse.RootPkg(pkgs)
 			t.Logf("root package: %s", rootPkg)
 			for _, pkg := range pkgs {
-				errs = addErrors(errs, size.Check(pkg, rootPkg, spec.givenMaxSize))
+				errs = addErrors(errs, size.Check(pkg, rootPkg, cfg))
 			}
 			if len(errs) != spec.expectedErrors {
 				t.Errorf("Expected %d errors but got %d: %q", spec.expectedErrors, len(errs), errs)
 			}
+			if spec.expectedMessage != "" && !containsPart(errs, spec.expectedMessage) {
+				t.Errorf("Expected error containing %q but got: %q", spec.expectedMessage, errs)
+			}
 		})
 	}
 }
@@ -60,10 +163,11 @@ func addErrors(allErrs []string, newErrs []error) []string {
 	return allErrs
 }
 
-func mustAbs(path string) string {
-	absPath, err := filepath.Abs(path)
-	if err != nil {
-		panic(err.Error())
+func containsPart(ss []string, s string) bool {
+	for _, t := range ss {
+		if strings.Contains(t, s) {
+			return true
+		}
 	}
-	return absPath
+	return false
 }
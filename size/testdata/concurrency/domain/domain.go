@@ -0,0 +1,18 @@
+package domain
+
+// Calculate sends the result of an expensive calculation.
+func Calculate(n int, out chan<- int) {
+	go func() {
+		out <- n * n
+	}()
+}
+
+// Wait waits for the calculation.
+func Wait(n int) int {
+	out := make(chan int, 1)
+	Calculate(n, out)
+	select {
+	case r := <-out:
+		return r
+	}
+}
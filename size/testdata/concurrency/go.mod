@@ -0,0 +1,3 @@
+module github.com/flowdev/spaghetti-cutter/size/testdata/concurrency
+
+go 1.14
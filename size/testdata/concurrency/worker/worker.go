@@ -0,0 +1,13 @@
+package worker
+
+// Run runs f in the background and returns its result.
+func Run(f func() int) int {
+	results := make(chan int)
+	go func() {
+		results <- f()
+	}()
+	select {
+	case r := <-results:
+		return r
+	}
+}
@@ -0,0 +1,6 @@
+package gen
+
+// Square returns the square of n.
+func Square(n int) int {
+	return n * n
+}
@@ -0,0 +1,3 @@
+module github.com/flowdev/spaghetti-cutter/size/testdata/generated-concurrency
+
+go 1.14
@@ -0,0 +1,6 @@
+package gen
+
+// Lookup returns the value for the given key.
+func Lookup(key string) string {
+	return table[key]
+}
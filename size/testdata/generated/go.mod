@@ -0,0 +1,3 @@
+module github.com/flowdev/spaghetti-cutter/size/testdata/generated
+
+go 1.14
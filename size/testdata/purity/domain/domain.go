@@ -0,0 +1,12 @@
+package domain
+
+var state int
+
+func init() {
+	state = 1
+}
+
+// State returns the state.
+func State() int {
+	return state
+}
@@ -0,0 +1,3 @@
+module github.com/flowdev/spaghetti-cutter/size/testdata/purity
+
+go 1.14
@@ -0,0 +1,39 @@
+package pure
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNotFound is a sentinel error.
+var ErrNotFound = errors.New("not found")
+
+var _ fmt.Stringer = name("")
+
+var cache = map[string]string{}
+
+// DefaultName is the default name.
+var DefaultName = "pure"
+
+func init() {
+	cache["a"] = "b"
+}
+
+type name string
+
+func (n name) String() string {
+	return string(n)
+}
+
+// Lookup looks up the value for the key.
+func Lookup(k string) (string, error) {
+	var local = cache[k]
+	f := func() error {
+		var err error
+		if local == "" {
+			err = ErrNotFound
+		}
+		return err
+	}
+	return local, f()
+}
@@ -0,0 +1,9 @@
+package sub
+
+var counter int
+
+// Count counts the calls.
+func Count() int {
+	counter++
+	return counter
+}
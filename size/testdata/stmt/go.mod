@@ -1,3 +0,0 @@
-module github.com/flowdev/spaghetti-cutter/size/testdata/stmt
-
-go 1.14
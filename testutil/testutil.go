@@ -0,0 +1,24 @@
+// Package testutil contains helpers that are shared by the tests of
+// multiple packages.
+package testutil
+
+import "path/filepath"
+
+// MustAbs returns the absolute version of the given path or panics.
+func MustAbs(path string) string {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		panic(err.Error())
+	}
+	return absPath
+}
+
+// ContainsString reports whether the slice ss contains the string s.
+func ContainsString(ss []string, s string) bool {
+	for _, t := range ss {
+		if t == s {
+			return true
+		}
+	}
+	return false
+}
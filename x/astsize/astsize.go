@@ -0,0 +1,95 @@
+// Package astsize computes the size/complexity of Go source code using
+// configurable weights for the main node categories.
+package astsize
+
+import (
+	"fmt"
+	"go/ast"
+)
+
+// Weights contains the weights of the main node categories that are used
+// for computing the size of Go source code.
+type Weights struct {
+	Ident          uint
+	BasicLit       uint
+	BasicLitLength uint // number of characters of a basic literal that add one to its size (0: ignore length)
+	CompositeLit   uint // additional to the size of the type and the elements
+	StructType     uint
+	InterfaceType  uint
+	FuncType       uint
+	FuncLit        uint // additional to the size of the type and the body
+	ValueName      uint // each name in a const or var declaration
+	Select         uint
+	Branch         uint
+	Label          uint
+	Go             uint
+	Defer          uint
+}
+
+// DefaultWeights returns the weights that are used if they aren't
+// configured explicitly.
+func DefaultWeights() Weights {
+	return Weights{
+		Ident:          1,
+		BasicLit:       1,
+		BasicLitLength: 32,
+		CompositeLit:   0,
+		StructType:     1,
+		InterfaceType:  1,
+		FuncType:       1,
+		FuncLit:        0,
+		ValueName:      1,
+		Select:         1,
+		Branch:         1,
+		Label:          1,
+		Go:             1,
+		Defer:          1,
+	}
+}
+
+// String implements Stringer and returns all weights in a fixed order.
+func (w Weights) String() string {
+	return fmt.Sprintf("`ident`: %d ; `basicLit`: %d ; `basicLitLength`: %d ; `compositeLit`: %d ; "+
+		"`structType`: %d ; `interfaceType`: %d ; `funcType`: %d ; `funcLit`: %d ; `valueName`: %d ; "+
+		"`select`: %d ; `branch`: %d ; `label`: %d ; `go`: %d ; `defer`: %d",
+		w.Ident, w.BasicLit, w.BasicLitLength, w.CompositeLit,
+		w.StructType, w.InterfaceType, w.FuncType, w.FuncLit, w.ValueName,
+		w.Select, w.Branch, w.Label, w.Go, w.Defer)
+}
+
+// Visitor is called for every declaration, statement and expression whose
+// size is computed. fun is the outermost function around the node: a function
+// declaration, a function literal at package level or nil at package level.
+type Visitor func(n ast.Node, fun ast.Node)
+
+// OfFile returns the size of the given file.
+func (w Weights) OfFile(astf *ast.File) uint {
+	return w.VisitFile(astf, nil)
+}
+
+// VisitFile returns the size of the given file and calls visit (if not nil)
+// for all nodes whose size is computed.
+// So other checks don't have to walk the file a second time.
+func (w Weights) VisitFile(astf *ast.File, visit Visitor) uint {
+	sz := &sizer{weights: w, visit: visit}
+	var size uint
+
+	for _, decl := range astf.Decls {
+		size += sz.sizeOfDecl(decl)
+	}
+	return size
+}
+
+// sizer computes the size of AST nodes using the weights.
+type sizer struct {
+	weights Weights
+	visit   Visitor
+	fun     ast.Node // outermost function around the current node
+}
+
+// see calls the visitor for the node if there is one.
+func (sz *sizer) see(n ast.Node) {
+	if sz.visit != nil {
+		sz.visit(n, sz.fun)
+	}
+}
@@ -0,0 +1,56 @@
+package astsize
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+)
+
+func TestVisitFile(t *testing.T) {
+	src := `package visit
+
+var start = func() { go run() }
+
+func run() {
+	go func() {}()
+}
+
+func (s *server) serve() {
+	var done chan bool
+	go s.listen(done)
+}
+`
+	astf, err := parser.ParseFile(token.NewFileSet(), "visit.go", src, 0)
+	if err != nil {
+		t.Fatalf("received unexpected error: %v", err)
+	}
+
+	var funs []string
+	visitedSize := DefaultWeights().VisitFile(astf, func(n ast.Node, fun ast.Node) {
+		if _, ok := n.(*ast.GoStmt); !ok {
+			return
+		}
+		switch f := fun.(type) {
+		case *ast.FuncDecl:
+			funs = append(funs, f.Name.Name)
+		case *ast.FuncLit:
+			funs = append(funs, "literal")
+		default:
+			funs = append(funs, "package")
+		}
+	})
+
+	expectedFuns := []string{"literal", "run", "serve"}
+	if len(funs) != len(expectedFuns) {
+		t.Fatalf("expected go statements in %q but got: %q", expectedFuns, funs)
+	}
+	for i, f := range expectedFuns {
+		if funs[i] != f {
+			t.Errorf("expected go statement %d in %q but got: %q", i+1, f, funs[i])
+		}
+	}
+	if size := DefaultWeights().OfFile(astf); visitedSize != size {
+		t.Errorf("expected the same size %d as without visitor but got: %d", size, visitedSize)
+	}
+}
@@ -0,0 +1,56 @@
+package astsize
+
+import (
+	"go/ast"
+	"log"
+)
+
+func (sz *sizer) sizeOfDecl(decl ast.Decl) uint {
+	var size uint
+
+	if isNilInterfaceOrPointer(decl) {
+		return 0
+	}
+	sz.see(decl)
+
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		size += sz.sizeOfFuncDecl(d)
+	case *ast.GenDecl:
+		size += sz.sizeOfGenDecl(d)
+	default:
+		size = 1
+		log.Printf("WARNING - Don't know size of unknown decl: %T", d)
+	}
+	return size
+}
+
+func (sz *sizer) sizeOfGenDecl(decl *ast.GenDecl) uint {
+	var size uint
+
+	for _, spec := range decl.Specs {
+		switch s := spec.(type) {
+		case *ast.TypeSpec:
+			size += sz.sizeOfExpr(s.Type)
+		case *ast.ValueSpec:
+			size += sz.weights.ValueName * uint(len(s.Names))
+			for _, v := range s.Values {
+				size += sz.sizeOfExpr(v)
+			}
+		}
+	}
+	return size
+}
+
+func (sz *sizer) sizeOfFuncDecl(fun *ast.FuncDecl) uint {
+	sz.fun = fun
+	defer func() { sz.fun = nil }()
+	size := sz.sizeOfFieldList(fun.Recv)
+	size += sz.sizeOfFuncType(fun.Type)
+	size += sz.sizeOfStmt(fun.Body)
+	return size
+}
+
+func (sz *sizer) sizeOfFuncType(fun *ast.FuncType) uint {
+	return sz.weights.FuncType + sz.sizeOfFieldList(fun.Params) + sz.sizeOfFieldList(fun.Results)
+}
@@ -1,4 +1,4 @@
-package size
+package astsize
 
 import (
 	"go/ast"
@@ -6,10 +6,11 @@ import (
 	"testing"
 
 	"github.com/flowdev/spaghetti-cutter/parse"
+	"github.com/flowdev/spaghetti-cutter/testutil"
 )
 
 func TestSizeOfDecl(t *testing.T) {
-	pkgs, err := parse.DirTree(mustAbs(filepath.Join("testdata", "decl")))
+	pkgs, _, err := parse.DirTree(testutil.MustAbs(filepath.Join("testdata", "decl")), nil)
 	if err != nil {
 		t.Fatalf("received unexpected error: %v", err)
 	}
@@ -45,7 +46,7 @@ func TestSizeOfDecl(t *testing.T) {
 					t.Errorf("unknown decl : %T", decl)
 				}
 				t.Run(id, func(t *testing.T) {
-					actualSize := sizeOfDecl(decl)
+					actualSize := defaultSizer().sizeOfDecl(decl)
 					if actualSize != expectedSize {
 						t.Errorf("expected size %d but got: %d", expectedSize, actualSize)
 					}
@@ -0,0 +1,138 @@
+package astsize
+
+import (
+	"go/ast"
+	"log"
+	"reflect"
+)
+
+func (sz *sizer) sizeOfExpr(expr ast.Expr) uint {
+	var size uint
+
+	if isNilInterfaceOrPointer(expr) {
+		return 0
+	}
+	sz.see(expr)
+
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		size = sz.sizeOfBasicLit(e)
+	case *ast.CompositeLit:
+		size = sz.sizeOfCompositeLit(e)
+	case *ast.Ident:
+		size = sz.sizeOfIdent(e)
+	case *ast.SelectorExpr:
+		size = sz.sizeOfExprs(e.X, e.Sel)
+	case *ast.UnaryExpr:
+		size = sz.sizeOfExpr(e.X)
+	case *ast.CallExpr:
+		size = sz.sizeOfCallExpr(e)
+	case *ast.KeyValueExpr:
+		size = sz.sizeOfExprs(e.Key, e.Value)
+	case *ast.StructType:
+		size = sz.sizeOfStructType(e)
+	case *ast.ArrayType:
+		size = sz.sizeOfExprs(e.Len, e.Elt)
+	case *ast.MapType:
+		size = sz.sizeOfExprs(e.Key, e.Value)
+	case *ast.ChanType:
+		size = sz.sizeOfExpr(e.Value)
+	case *ast.InterfaceType:
+		size = sz.sizeOfInterfaceType(e)
+	case *ast.FuncType:
+		size = sz.sizeOfFuncType(e)
+	case *ast.FuncLit:
+		size = sz.sizeOfFuncLit(e)
+	case *ast.TypeAssertExpr:
+		size = sz.sizeOfExprs(e.X, e.Type)
+	case *ast.StarExpr:
+		size = sz.sizeOfExpr(e.X)
+	case *ast.SliceExpr:
+		size = sz.sizeOfExprs(e.X, e.Low, e.High, e.Max)
+	case *ast.IndexExpr:
+		size = sz.sizeOfExprs(e.X, e.Index)
+	case *ast.BinaryExpr:
+		size = sz.sizeOfExprs(e.X, e.Y)
+	case *ast.ParenExpr:
+		size = sz.sizeOfExpr(e.X)
+	case *ast.Ellipsis:
+		size = sz.sizeOfExpr(e.Elt)
+	case nil:
+		size = 0
+	default:
+		size = 1
+		log.Printf("WARNING - Don't know size of unknown expr: %T", e)
+	}
+	return size
+}
+
+func (sz *sizer) sizeOfIdent(id *ast.Ident) uint {
+	return sz.weights.Ident
+}
+
+func (sz *sizer) sizeOfBasicLit(lit *ast.BasicLit) uint {
+	size := sz.weights.BasicLit
+	if sz.weights.BasicLitLength > 0 {
+		size += uint(len(lit.Value)) / sz.weights.BasicLitLength
+	}
+	return size
+}
+
+func (sz *sizer) sizeOfCompositeLit(lit *ast.CompositeLit) uint {
+	return sz.weights.CompositeLit + sz.sizeOfExpr(lit.Type) + sz.sizeOfExprs(lit.Elts...)
+}
+
+func (sz *sizer) sizeOfStructType(typ *ast.StructType) uint {
+	return sz.weights.StructType + sz.sizeOfFieldList(typ.Fields)
+}
+
+func (sz *sizer) sizeOfFieldList(list *ast.FieldList) uint {
+	if list == nil {
+		return 0
+	}
+
+	var size uint
+
+	for _, field := range list.List {
+		size += sz.sizeOfField(field)
+	}
+	return size
+}
+
+func (sz *sizer) sizeOfField(field *ast.Field) uint {
+	if field == nil {
+		return 0
+	}
+
+	return sz.sizeOfExpr(field.Type) + sz.sizeOfExpr(field.Tag)
+}
+
+func (sz *sizer) sizeOfFuncLit(fun *ast.FuncLit) uint {
+	if sz.fun == nil { // function literal at package level
+		sz.fun = fun
+		defer func() { sz.fun = nil }()
+	}
+	return sz.weights.FuncLit + sz.sizeOfExpr(fun.Type) + sz.sizeOfStmt(fun.Body)
+}
+
+func (sz *sizer) sizeOfCallExpr(call *ast.CallExpr) uint {
+	return sz.sizeOfExpr(call.Fun) + sz.sizeOfExprs(call.Args...)
+}
+
+func (sz *sizer) sizeOfInterfaceType(iface *ast.InterfaceType) uint {
+	return sz.weights.InterfaceType + sz.sizeOfFieldList(iface.Methods)
+}
+
+func (sz *sizer) sizeOfExprs(exprs ...ast.Expr) uint {
+	var size uint
+
+	for _, expr := range exprs {
+		size += sz.sizeOfExpr(expr)
+	}
+	return size
+}
+
+func isNilInterfaceOrPointer(v interface{}) bool {
+	return v == nil ||
+		(reflect.ValueOf(v).Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil())
+}
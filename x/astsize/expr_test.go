@@ -1,4 +1,4 @@
-package size
+package astsize
 
 import (
 	"go/ast"
@@ -8,10 +8,11 @@ import (
 	"testing"
 
 	"github.com/flowdev/spaghetti-cutter/parse"
+	"github.com/flowdev/spaghetti-cutter/testutil"
 )
 
 func TestSizeOfExpr(t *testing.T) {
-	pkgs, err := parse.DirTree(mustAbs(filepath.Join("testdata", "expr")))
+	pkgs, _, err := parse.DirTree(testutil.MustAbs(filepath.Join("testdata", "expr")), nil)
 	if err != nil {
 		t.Fatalf("received unexpected error: %v", err)
 	}
@@ -30,7 +31,7 @@ func TestSizeOfExpr(t *testing.T) {
 							for i, name := range s.Names { // names and values
 								id, expectedSize := nameToIDandSize(name.Name)
 								t.Run(id, func(t *testing.T) {
-									actualSize := sizeOfExpr(s.Values[i])
+									actualSize := defaultSizer().sizeOfExpr(s.Values[i])
 									if actualSize != expectedSize {
 										t.Errorf("expected size %d but got: %d", expectedSize, actualSize)
 									}
@@ -68,10 +69,6 @@ func nameToIDandSize(name string) (id string, size uint) {
 	return parts[0], uint(i)
 }
 
-func mustAbs(path string) string {
-	absPath, err := filepath.Abs(path)
-	if err != nil {
-		panic(err.Error())
-	}
-	return absPath
+func defaultSizer() *sizer {
+	return &sizer{weights: DefaultWeights()}
 }
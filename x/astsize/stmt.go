@@ -0,0 +1,157 @@
+package astsize
+
+import (
+	"go/ast"
+	"log"
+)
+
+func (sz *sizer) sizeOfStmt(stmt ast.Stmt) uint {
+	var size uint
+
+	if isNilInterfaceOrPointer(stmt) {
+		return 0
+	}
+	sz.see(stmt)
+
+	switch s := stmt.(type) {
+	case *ast.AssignStmt:
+		size = sz.sizeOfAssignStmt(s)
+	case *ast.IncDecStmt:
+		size = sz.sizeOfIncDecStmt(s)
+	case *ast.ReturnStmt:
+		size = sz.sizeOfReturnStmt(s)
+	case *ast.ExprStmt:
+		size = sz.sizeOfExprStmt(s)
+	case *ast.IfStmt:
+		size = sz.sizeOfIfStmt(s)
+	case *ast.ForStmt:
+		size = sz.sizeOfForStmt(s)
+	case *ast.RangeStmt:
+		size = sz.sizeOfRangeStmt(s)
+	case *ast.BlockStmt:
+		size = sz.sizeOfBlockStmt(s)
+	case *ast.SwitchStmt:
+		size = sz.sizeOfSwitchStmt(s)
+	case *ast.TypeSwitchStmt:
+		size = sz.sizeOfTypeSwitchStmt(s)
+	case *ast.CaseClause:
+		size = sz.sizeOfCaseClause(s)
+	case *ast.SelectStmt:
+		size = sz.sizeOfSelectStmt(s)
+	case *ast.CommClause:
+		size = sz.sizeOfCommClause(s)
+	case *ast.SendStmt:
+		size = sz.sizeOfSendStmt(s)
+	case *ast.BranchStmt:
+		size = sz.sizeOfBranchStmt(s)
+	case *ast.GoStmt:
+		size = sz.sizeOfGoStmt(s)
+	case *ast.LabeledStmt:
+		size = sz.sizeOfLabeledStmt(s)
+	case *ast.DeferStmt:
+		size = sz.sizeOfDeferStmt(s)
+	case *ast.DeclStmt:
+		size = sz.sizeOfDeclStmt(s)
+	case *ast.EmptyStmt:
+		size = 0
+	case nil:
+		size = 0
+	default:
+		size = 1
+		log.Printf("WARNING - Don't know size of unknown stmt: %T", s)
+	}
+	return size
+}
+
+func (sz *sizer) sizeOfBlockStmt(block *ast.BlockStmt) uint {
+	var size uint
+
+	for _, stmt := range block.List {
+		size += sz.sizeOfStmt(stmt)
+	}
+	return size
+}
+
+func (sz *sizer) sizeOfAssignStmt(assign *ast.AssignStmt) uint {
+	return sz.sizeOfExprs(assign.Lhs...) + sz.sizeOfExprs(assign.Rhs...)
+}
+
+func (sz *sizer) sizeOfReturnStmt(ret *ast.ReturnStmt) uint {
+	return sz.sizeOfExprs(ret.Results...)
+}
+
+func (sz *sizer) sizeOfRangeStmt(rng *ast.RangeStmt) uint {
+	return sz.sizeOfExprs(rng.Key, rng.Value, rng.X) + sz.sizeOfStmt(rng.Body)
+}
+
+func (sz *sizer) sizeOfIfStmt(ifs *ast.IfStmt) uint {
+	return sz.sizeOfStmt(ifs.Init) + sz.sizeOfExpr(ifs.Cond) +
+		sz.sizeOfStmt(ifs.Body) + sz.sizeOfStmt(ifs.Else)
+}
+
+func (sz *sizer) sizeOfForStmt(fors *ast.ForStmt) uint {
+	return sz.sizeOfStmt(fors.Init) + sz.sizeOfExpr(fors.Cond) + sz.sizeOfStmt(fors.Post) +
+		sz.sizeOfStmt(fors.Body)
+}
+
+func (sz *sizer) sizeOfSwitchStmt(swtch *ast.SwitchStmt) uint {
+	return sz.sizeOfStmt(swtch.Init) + sz.sizeOfExpr(swtch.Tag) +
+		sz.sizeOfStmt(swtch.Body)
+}
+
+func (sz *sizer) sizeOfTypeSwitchStmt(typswitch *ast.TypeSwitchStmt) uint {
+	return sz.sizeOfStmt(typswitch.Init) + sz.sizeOfStmt(typswitch.Assign) +
+		sz.sizeOfStmt(typswitch.Body)
+}
+
+func (sz *sizer) sizeOfCaseClause(clause *ast.CaseClause) uint {
+	size := sz.sizeOfExprs(clause.List...)
+	for _, stmt := range clause.Body {
+		size += sz.sizeOfStmt(stmt)
+	}
+	return size
+}
+
+func (sz *sizer) sizeOfSelectStmt(sel *ast.SelectStmt) uint {
+	return sz.weights.Select + sz.sizeOfStmt(sel.Body)
+}
+
+func (sz *sizer) sizeOfCommClause(clause *ast.CommClause) uint {
+	size := sz.sizeOfStmt(clause.Comm)
+	for _, stmt := range clause.Body {
+		size += sz.sizeOfStmt(stmt)
+	}
+	return size
+}
+
+func (sz *sizer) sizeOfDeclStmt(decl *ast.DeclStmt) uint {
+	return sz.sizeOfDecl(decl.Decl)
+}
+
+func (sz *sizer) sizeOfBranchStmt(branch *ast.BranchStmt) uint {
+	return sz.weights.Branch
+}
+
+func (sz *sizer) sizeOfLabeledStmt(label *ast.LabeledStmt) uint {
+	return sz.weights.Label + sz.sizeOfStmt(label.Stmt) // labels add cognitive load
+}
+
+func (sz *sizer) sizeOfGoStmt(gos *ast.GoStmt) uint {
+	return sz.weights.Go + sz.sizeOfExpr(gos.Call)
+}
+
+func (sz *sizer) sizeOfSendStmt(send *ast.SendStmt) uint {
+	return sz.sizeOfExprs(send.Chan, send.Value)
+}
+
+func (sz *sizer) sizeOfDeferStmt(defe *ast.DeferStmt) uint {
+	return sz.weights.Defer + sz.sizeOfExpr(defe.Call)
+}
+
+func (sz *sizer) sizeOfIncDecStmt(incdec *ast.IncDecStmt) uint {
+	return sz.sizeOfExpr(incdec.X)
+}
+
+func (sz *sizer) sizeOfExprStmt(expr *ast.ExprStmt) uint {
+	return sz.sizeOfExpr(expr.X)
+}
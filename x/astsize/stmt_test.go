@@ -1,4 +1,4 @@
-package size
+package astsize
 
 import (
 	"go/ast"
@@ -6,10 +6,11 @@ import (
 	"testing"
 
 	"github.com/flowdev/spaghetti-cutter/parse"
+	"github.com/flowdev/spaghetti-cutter/testutil"
 )
 
 func TestSizeOfStmt(t *testing.T) {
-	pkgs, err := parse.DirTree(mustAbs(filepath.Join("testdata", "stmt")))
+	pkgs, _, err := parse.DirTree(testutil.MustAbs(filepath.Join("testdata", "stmt")), nil)
 	if err != nil {
 		t.Fatalf("received unexpected error: %v", err)
 	}
@@ -23,7 +24,7 @@ func TestSizeOfStmt(t *testing.T) {
 					t.Run(id, func(t *testing.T) {
 						actualSize := uint(0)
 						for _, stmt := range d.Body.List {
-							actualSize += sizeOfStmt(stmt)
+							actualSize += defaultSizer().sizeOfStmt(stmt)
 						}
 						if actualSize != expectedSize {
 							t.Errorf("expected size %d but got: %d", expectedSize, actualSize)
@@ -0,0 +1,3 @@
+module github.com/flowdev/spaghetti-cutter/x/astsize/testdata/decl
+
+go 1.14
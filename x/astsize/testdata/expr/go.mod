@@ -0,0 +1,5 @@
+module github.com/flowdev/spaghetti-cutter/x/astsize/testdata/tstexpr
+
+go 1.14
+
+require github.com/flowdev/spaghetti-cutter v0.0.0-20200512121203-24f3efaf7aea
@@ -0,0 +1,3 @@
+module github.com/flowdev/spaghetti-cutter/x/astsize/testdata/stmt
+
+go 1.14
@@ -5,13 +5,14 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/flowdev/spaghetti-cutter/testutil"
 	"github.com/flowdev/spaghetti-cutter/x/dirs"
 )
 
 const testFile = ".test-file"
 
 func TestFindRoot(t *testing.T) {
-	testDataDir := mustAbs(filepath.Join("testdata", "find-root"))
+	testDataDir := testutil.MustAbs(filepath.Join("testdata", "find-root"))
 	specs := []struct {
 		name              string
 		givenCWD          string
@@ -34,7 +35,7 @@ func TestFindRoot(t *testing.T) {
 		},
 	}
 
-	initDir := mustAbs(".")
+	initDir := testutil.MustAbs(".")
 	t.Cleanup(func() {
 		mustChdir(initDir)
 	})
@@ -60,11 +61,3 @@ func mustChdir(path string) {
 		panic(err.Error())
 	}
 }
-
-func mustAbs(path string) string {
-	absPath, err := filepath.Abs(path)
-	if err != nil {
-		panic(err.Error())
-	}
-	return absPath
-}
@@ -0,0 +1,52 @@
+// Package git runs the local git binary to find out about changes.
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// ChangedFiles returns the files that changed since the given revision.
+// The working tree is compared, so uncommitted changes of tracked files and
+// new files that aren't ignored are included.
+// The file names are relative to dir and only files below dir are returned.
+func ChangedFiles(dir, rev string) ([]string, error) {
+	out, err := run(dir, "diff", "--name-only", "--no-renames", "--relative", rev, "--")
+	if err != nil {
+		return nil, err
+	}
+	untracked, err := run(dir, "ls-files", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, line := range strings.Split(string(out)+string(untracked), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
+
+// FileAt returns the content of the given file at the given revision.
+// The file name has to be relative to dir.
+func FileAt(dir, rev, file string) ([]byte, error) {
+	return run(dir, "show", rev+":./"+file)
+}
+
+func run(dir string, args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("unable to run 'git %s' in directory %q: %w: %s",
+			strings.Join(args, " "), dir, err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
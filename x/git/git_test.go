@@ -0,0 +1,151 @@
+package git_test
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/flowdev/spaghetti-cutter/x/git"
+)
+
+func TestChangedFiles(t *testing.T) {
+	specs := []struct {
+		name           string
+		givenModified  []string
+		givenUntracked []string
+		givenDir       string
+		givenRev       string
+		expectedFiles  []string
+		expectedError  bool
+	}{
+		{
+			name:          "nothing-changed",
+			givenRev:      "HEAD",
+			expectedFiles: nil,
+		}, {
+			name:          "modified",
+			givenModified: []string{"main.go", "sub/sub.go"},
+			givenRev:      "HEAD",
+			expectedFiles: []string{"main.go", "sub/sub.go"},
+		}, {
+			name:           "untracked",
+			givenUntracked: []string{"new.go", "sub/new.go", "ignored.log"},
+			givenRev:       "HEAD",
+			expectedFiles:  []string{"new.go", "sub/new.go"},
+		}, {
+			name:           "relative-subdirectory",
+			givenModified:  []string{"main.go", "sub/sub.go"},
+			givenUntracked: []string{"new.go", "sub/new.go"},
+			givenDir:       "sub",
+			givenRev:       "HEAD",
+			expectedFiles:  []string{"new.go", "sub.go"},
+		}, {
+			name:          "unknown-revision",
+			givenRev:      "no-such-revision-for-sure",
+			expectedError: true,
+		},
+	}
+
+	for _, spec := range specs {
+		t.Run(spec.name, func(t *testing.T) {
+			root := mustGitRepo(t)
+			for _, f := range spec.givenModified {
+				mustWriteFile(t, filepath.Join(root, f), "package modified\n")
+			}
+			for _, f := range spec.givenUntracked {
+				mustWriteFile(t, filepath.Join(root, f), "package untracked\n")
+			}
+
+			actualFiles, err := git.ChangedFiles(filepath.Join(root, spec.givenDir), spec.givenRev)
+			if spec.expectedError {
+				if err == nil {
+					t.Error("expected to receive error but didn't get one")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("received UNexpected error: %v", err)
+			}
+			sort.Strings(actualFiles)
+			if !reflect.DeepEqual(actualFiles, spec.expectedFiles) {
+				t.Errorf("expected files %q, actual %q", spec.expectedFiles, actualFiles)
+			}
+		})
+	}
+}
+
+// mustGitRepo creates a temporary git repository with a committed main.go,
+// sub/sub.go and a .gitignore file that ignores log files.
+func mustGitRepo(t *testing.T) string {
+	root := t.TempDir()
+	mustWriteFile(t, filepath.Join(root, ".gitignore"), "*.log\n")
+	mustWriteFile(t, filepath.Join(root, "main.go"), "package main\n")
+	mustWriteFile(t, filepath.Join(root, "sub", "sub.go"), "package sub\n")
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"add", "."},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "-m", "initial"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = root
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("unable to run 'git %s': %v: %s", strings.Join(args, " "), err, out)
+		}
+	}
+	return root
+}
+
+func mustWriteFile(t *testing.T, file, content string) {
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		t.Fatalf("unable to create directory of file %q: %v", file, err)
+	}
+	if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatalf("unable to write file %q: %v", file, err)
+	}
+}
+
+func TestFileAt(t *testing.T) {
+	specs := []struct {
+		name          string
+		givenDir      string
+		givenFile     string
+		expectedStart string
+		expectedError bool
+	}{
+		{
+			name:          "existing-file",
+			givenDir:      "../..",
+			givenFile:     "LICENSE",
+			expectedStart: "MIT License",
+			expectedError: false,
+		}, {
+			name:          "missing-file",
+			givenDir:      ".",
+			givenFile:     "no-such-file-for-sure.go",
+			expectedError: true,
+		},
+	}
+
+	for _, spec := range specs {
+		t.Run(spec.name, func(t *testing.T) {
+			content, err := git.FileAt(spec.givenDir, "HEAD", spec.givenFile)
+			if spec.expectedError {
+				if err == nil {
+					t.Error("expected to receive error but didn't get one")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("received UNexpected error: %v", err)
+			}
+			if actualStart := string(content[:len(spec.expectedStart)]); actualStart != spec.expectedStart {
+				t.Errorf("expected file to start with %q, actual %q", spec.expectedStart, actualStart)
+			}
+		})
+	}
+}
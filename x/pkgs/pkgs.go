@@ -1,6 +1,9 @@
 package pkgs
 
 import (
+	"go/ast"
+	"regexp"
+	"sort"
 	"strings"
 
 	"golang.org/x/tools/go/packages"
@@ -10,27 +13,48 @@ const pkgMain = "main"
 
 const testSuffix = ".test"
 
+// generatedRegexp matches the comment that marks generated files
+// (see: https://golang.org/s/generatedcode).
+var generatedRegexp = regexp.MustCompile(`^// Code generated .* DO NOT EDIT\.$`)
+
 // Package is a type alias so other packages can save the import
 type Package = packages.Package
 
-// PkgType is an enum with values like 'Tool' and 'HalfDB'
-type PkgType int
+// PkgType is the name of a package type like 'tool' or 'db sub-package'.
+// Besides the built-in types below, types can be defined in the configuration.
+type PkgType string
 
+// The built-in package types.
 const (
-	PkgTypeStandard PkgType = iota
-	PkgTypeHalfTool
-	PkgTypeTool
-	PkgTypeHalfDB
-	PkgTypeDB
-	PkgTypeGod
+	PkgTypeStandard  PkgType = "standard"
+	PkgTypeHalfTool  PkgType = "tool sub-package"
+	PkgTypeTool      PkgType = "tool"
+	PkgTypeHalfDB    PkgType = "db sub-package"
+	PkgTypeDB        PkgType = "db"
+	PkgTypeGod       PkgType = "god"
+	PkgTypeGenerated PkgType = "generated"
+	PkgTypeTestOnly  PkgType = "testOnly"
 )
 
+// SubPackageType returns the type of the sub-packages of packages of type t.
+func SubPackageType(t PkgType) PkgType {
+	return t + " sub-package"
+}
+
+// String implements Stringer and returns the name of the type.
+func (t PkgType) String() string {
+	return string(t)
+}
+
 type PackageInfo struct {
 	UniqName string
 	Size     int
 	Type     PkgType
-	Deps     []*PackageInfo
+	Deps     []*PackageInfo // internal packages imported by this package
+	Users    []*PackageInfo // internal non-test packages importing this package
+	ExtDeps  []string       // external packages (not from the standard library) imported by this package
 	Pkg      *Package
+	TestPkg  *Package // variant of the package that includes its in-package tests (nil if it has none)
 }
 
 // RelativePackageName return the package name relative towards the given root package.
@@ -47,6 +71,17 @@ func RelativePackageName(pkg *Package, rootPkg string) (relPkg, strictRelPkg str
 	}
 	return strictRelativePkg(relPkg), ""
 }
+
+// RelativeImportName returns the relative names of the packages of the
+// project (like RelativePackageName). For all other packages the namespaced
+// path and the full path (as strict name) are returned.
+func RelativeImportName(imp *Package, rootPkg string) (relImp, strictRelImp string) {
+	if strings.HasPrefix(imp.PkgPath, rootPkg) {
+		return RelativePackageName(imp, rootPkg)
+	}
+	return NamespacedName(imp.PkgPath), imp.PkgPath
+}
+
 func strictRelativePkg(rawRelPkg string) string {
 	if rawRelPkg == "" {
 		return "/"
@@ -71,6 +106,8 @@ func UniquePackageName(relPkg, strictRelPkg string) string {
 }
 
 // UniquePackages makes the given list of packages unique.
+// The variant of a package that includes its in-package tests is kept as
+// TestPkg.
 func UniquePackages(pkgs []*Package) map[string]*PackageInfo {
 	uniqPkgs := make(map[string]*PackageInfo, len(pkgs))
 
@@ -78,17 +115,100 @@ func UniquePackages(pkgs []*Package) map[string]*PackageInfo {
 		relPkg, strictRelPkg := RelativePackageName(pkg, "")
 		uniqName := UniquePackageName(relPkg, strictRelPkg)
 
-		if _, ok := uniqPkgs[uniqName]; !ok {
-			uniqPkg := &PackageInfo{
+		uniqPkg, ok := uniqPkgs[uniqName]
+		switch {
+		case !ok:
+			uniqPkgs[uniqName] = &PackageInfo{
 				UniqName: uniqName,
 				Pkg:      pkg,
 			}
-			uniqPkgs[uniqName] = uniqPkg
+		case uniqPkg.Pkg.PkgPath != pkg.PkgPath:
+			// the generated test main package `pkg.test` has got the same unique
+			// name as `pkg` but isn't part of the project, so it is ignored
+		case isInPackageTestVariant(pkg) && uniqPkg.TestPkg == nil:
+			uniqPkg.TestPkg = pkg
+		case isInPackageTestVariant(uniqPkg.Pkg) && !IsTestPackage(pkg):
+			uniqPkg.Pkg, uniqPkg.TestPkg = pkg, uniqPkg.Pkg
 		}
 	}
 	return uniqPkgs
 }
 
+// LinkPackages fills the dependencies and users of the given unique packages.
+// Only the given packages are internal. Packages with a path starting with
+// the root package are ignored if they aren't given (e.g. excluded ones).
+func LinkPackages(uniqPkgs map[string]*PackageInfo, rootPkg string) {
+	for _, name := range sortedNames(uniqPkgs) {
+		pkgInfo := uniqPkgs[name]
+		pkgInfo.Deps, pkgInfo.ExtDeps = nil, nil
+		for _, imp := range pkgInfo.Pkg.Imports {
+			if dep, ok := uniqPkgs[imp.PkgPath]; ok {
+				if dep != pkgInfo {
+					pkgInfo.Deps = append(pkgInfo.Deps, dep)
+				}
+			} else if !strings.HasPrefix(imp.PkgPath, rootPkg) && !IsStandardPackage(imp.PkgPath) {
+				pkgInfo.ExtDeps = append(pkgInfo.ExtDeps, imp.PkgPath)
+			}
+		}
+		sort.Slice(pkgInfo.Deps, func(i, j int) bool { return pkgInfo.Deps[i].UniqName < pkgInfo.Deps[j].UniqName })
+		sort.Strings(pkgInfo.ExtDeps)
+	}
+	for _, pkgInfo := range uniqPkgs {
+		pkgInfo.Users = nil
+	}
+	for _, name := range sortedNames(uniqPkgs) {
+		pkgInfo := uniqPkgs[name]
+		if strings.HasSuffix(name, "_test") {
+			continue
+		}
+		for _, dep := range pkgInfo.Deps {
+			dep.Users = append(dep.Users, pkgInfo)
+		}
+	}
+}
+
+func sortedNames(uniqPkgs map[string]*PackageInfo) []string {
+	names := make([]string, 0, len(uniqPkgs))
+	for name := range uniqPkgs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Namespaces of packages that don't belong to the project.
+// They can be used in patterns to match only packages of the standard library
+// (e.g. `std:net/**`) or only external packages (e.g. `ext:**`).
+const (
+	NamespaceStd = "std:"
+	NamespaceExt = "ext:"
+)
+
+// NamespacedName returns the given package path prefixed with its namespace
+// (NamespaceStd or NamespaceExt).
+func NamespacedName(path string) string {
+	if IsStandardPackage(path) {
+		return NamespaceStd + path
+	}
+	return NamespaceExt + path
+}
+
+// IsStandardPackage returns true if the given package path belongs to the
+// standard library (the first element of the path doesn't contain a dot).
+func IsStandardPackage(path string) bool {
+	first := path
+	if i := strings.IndexByte(path, '/'); i >= 0 {
+		first = path[:i]
+	}
+	return !strings.Contains(first, ".")
+}
+
+// isInPackageTestVariant returns true if the given package is the variant of
+// a package that includes its in-package tests (ID: `pkg [pkg.test]`).
+func isInPackageTestVariant(pkg *Package) bool {
+	return strings.HasSuffix(pkg.ID, ".test]") && !strings.HasSuffix(pkg.PkgPath, "_test")
+}
+
 // IsTestPackage returns true if the given package is a test package and false
 // otherwise.
 func IsTestPackage(pkg *Package) bool {
@@ -98,3 +218,58 @@ func IsTestPackage(pkg *Package) bool {
 		strings.HasSuffix(pkg.ID, ".test")
 	return result
 }
+
+// IsGeneratedFile returns true if the given file contains the comment
+// `// Code generated ... DO NOT EDIT.` before its package clause.
+func IsGeneratedFile(astf *ast.File) bool {
+	for _, cg := range astf.Comments {
+		if cg.Pos() >= astf.Package {
+			return false
+		}
+		for _, c := range cg.List {
+			if generatedRegexp.MatchString(c.Text) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// FuncName returns the name of the declared function or method:
+// `Func` or `Recv.Method`.
+func FuncName(fun *ast.FuncDecl) string {
+	if fun.Recv == nil || len(fun.Recv.List) == 0 {
+		return fun.Name.Name
+	}
+	recv := fun.Recv.List[0].Type
+	for {
+		switch t := recv.(type) {
+		case *ast.StarExpr:
+			recv = t.X
+			continue
+		case *ast.ParenExpr:
+			recv = t.X
+			continue
+		case *ast.IndexExpr: // generic receiver
+			recv = t.X
+			continue
+		case *ast.Ident:
+			return t.Name + "." + fun.Name.Name
+		}
+		return fun.Name.Name
+	}
+}
+
+// IsGeneratedPackage returns true if all files of the given package are
+// generated and false otherwise.
+func IsGeneratedPackage(pkg *Package) bool {
+	if len(pkg.Syntax) == 0 {
+		return false
+	}
+	for _, astf := range pkg.Syntax {
+		if !IsGeneratedFile(astf) {
+			return false
+		}
+	}
+	return true
+}
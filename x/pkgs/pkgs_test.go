@@ -1,6 +1,10 @@
 package pkgs_test
 
 import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path"
 	"testing"
 
 	"github.com/flowdev/spaghetti-cutter/x/pkgs"
@@ -74,6 +78,47 @@ func TestRelativePackageName(t *testing.T) {
 	}
 }
 
+func TestRelativeImportName(t *testing.T) {
+	givenRootPkg := "github.com/flowdev/spaghetti-cutter"
+	specs := []struct {
+		name                 string
+		givenPkgPath         string
+		expectedRelImp       string
+		expectedStrictRelImp string
+	}{
+		{
+			name:                 "internal-package",
+			givenPkgPath:         "github.com/flowdev/spaghetti-cutter/x/config",
+			expectedRelImp:       "x/config",
+			expectedStrictRelImp: "",
+		}, {
+			name:                 "external-package",
+			givenPkgPath:         "golang.org/x/tools/go/packages",
+			expectedRelImp:       "ext:golang.org/x/tools/go/packages",
+			expectedStrictRelImp: "golang.org/x/tools/go/packages",
+		}, {
+			name:                 "standard-package",
+			givenPkgPath:         "database/sql",
+			expectedRelImp:       "std:database/sql",
+			expectedStrictRelImp: "database/sql",
+		},
+	}
+
+	for _, spec := range specs {
+		t.Run(spec.name, func(t *testing.T) {
+			pkg := &packages.Package{PkgPath: spec.givenPkgPath, Name: path.Base(spec.givenPkgPath)}
+			actualRelImp, actualStrictRelImp := pkgs.RelativeImportName(pkg, givenRootPkg)
+			if actualRelImp != spec.expectedRelImp {
+				t.Errorf("expected relative import %q, actual %q", spec.expectedRelImp, actualRelImp)
+			}
+			if actualStrictRelImp != spec.expectedStrictRelImp {
+				t.Errorf("expected strict relative import %q, actual %q",
+					spec.expectedStrictRelImp, actualStrictRelImp)
+			}
+		})
+	}
+}
+
 func TestUniquePackageName(t *testing.T) {
 	specs := []struct {
 		name              string
@@ -109,13 +154,24 @@ func TestUniquePackages(t *testing.T) {
 		name          string
 		givenPkgNames []string
 		givenPkgPaths []string
+		givenPkgIDs   []string // the names are used if empty
 		expectedLen   int
+		expectedTests int
 	}{
 		{
 			name:          "standard package",
 			givenPkgNames: []string{"config", "config_test", "main", "config"},
 			givenPkgPaths: []string{"x/config", "x/config_test", "cmd/my_service/main.test", "x/config"},
 			expectedLen:   3,
+		}, {
+			name:          "in-package tests",
+			givenPkgNames: []string{"config", "config", "config_test", "config", "main"},
+			givenPkgPaths: []string{"x/config", "x/config", "x/config_test", "x/dirs", "x/config.test"},
+			givenPkgIDs: []string{
+				"x/config", "x/config [x/config.test]", "x/config_test [x/config.test]", "x/dirs", "x/config.test",
+			},
+			expectedLen:   3,
+			expectedTests: 1,
 		},
 	}
 
@@ -128,11 +184,26 @@ func TestUniquePackages(t *testing.T) {
 					Name:    name,
 					PkgPath: spec.givenPkgPaths[i],
 				}
+				if len(spec.givenPkgIDs) > 0 {
+					packs[i].ID = spec.givenPkgIDs[i]
+				}
 			}
 			uniqPkgs := pkgs.UniquePackages(packs)
 			if len(uniqPkgs) != spec.expectedLen {
 				t.Errorf("expected length %d, actual %d %v", spec.expectedLen, len(uniqPkgs), uniqPkgs)
 			}
+			tests := 0
+			for _, pkgInfo := range uniqPkgs {
+				if pkgInfo.TestPkg != nil {
+					tests++
+					if pkgs.IsTestPackage(pkgInfo.Pkg) {
+						t.Errorf("expected package without tests for %q, actual %q", pkgInfo.UniqName, pkgInfo.Pkg.ID)
+					}
+				}
+			}
+			if tests != spec.expectedTests {
+				t.Errorf("expected %d packages with in-package tests, actual %d", spec.expectedTests, tests)
+			}
 		})
 	}
 }
@@ -170,3 +241,116 @@ func TestIsTestPackage(t *testing.T) {
 		})
 	}
 }
+
+func TestIsStandardPackage(t *testing.T) {
+	specs := []struct {
+		name               string
+		givenPkgPath       string
+		expectedIsStandard bool
+	}{
+		{
+			name:               "simple",
+			givenPkgPath:       "fmt",
+			expectedIsStandard: true,
+		}, {
+			name:               "nested",
+			givenPkgPath:       "net/http/httptest",
+			expectedIsStandard: true,
+		}, {
+			name:               "external",
+			givenPkgPath:       "github.com/lib/pq",
+			expectedIsStandard: false,
+		}, {
+			name:               "external-without-slash",
+			givenPkgPath:       "gopkg.in",
+			expectedIsStandard: false,
+		},
+	}
+
+	for _, spec := range specs {
+		t.Run(spec.name, func(t *testing.T) {
+			actualIsStandard := pkgs.IsStandardPackage(spec.givenPkgPath)
+			if actualIsStandard != spec.expectedIsStandard {
+				t.Errorf("expected %t, actual %t", spec.expectedIsStandard, actualIsStandard)
+			}
+		})
+	}
+}
+
+func TestIsGeneratedFile(t *testing.T) {
+	specs := []struct {
+		name                string
+		givenSource         string
+		expectedIsGenerated bool
+	}{
+		{
+			name:                "no-comment",
+			givenSource:         "package a\n",
+			expectedIsGenerated: false,
+		}, {
+			name:                "protobuf",
+			givenSource:         "// Code generated by protoc-gen-go. DO NOT EDIT.\n// source: a.proto\n\npackage a\n",
+			expectedIsGenerated: true,
+		}, {
+			name:                "after-doc-comment",
+			givenSource:         "// Package a is great.\n//\n// Code generated by mockery. DO NOT EDIT.\npackage a\n",
+			expectedIsGenerated: true,
+		}, {
+			name:                "after-package-clause",
+			givenSource:         "package a\n\n// Code generated by hand. DO NOT EDIT.\n",
+			expectedIsGenerated: false,
+		}, {
+			name:                "almost",
+			givenSource:         "// Code generated by sqlc. DO NOT EDIT\npackage a\n",
+			expectedIsGenerated: false,
+		},
+	}
+
+	for _, spec := range specs {
+		t.Run(spec.name, func(t *testing.T) {
+			astf, err := parser.ParseFile(token.NewFileSet(), spec.name+".go", spec.givenSource, parser.ParseComments)
+			if err != nil {
+				t.Fatalf("got unexpected error: %v", err)
+			}
+			actualIsGenerated := pkgs.IsGeneratedFile(astf)
+			if actualIsGenerated != spec.expectedIsGenerated {
+				t.Errorf("expected %t, actual %t", spec.expectedIsGenerated, actualIsGenerated)
+			}
+		})
+	}
+}
+
+func TestFuncName(t *testing.T) {
+	specs := []struct {
+		name         string
+		givenSource  string
+		expectedName string
+	}{
+		{
+			name:         "func",
+			givenSource:  "package a\nfunc F() {}\n",
+			expectedName: "F",
+		}, {
+			name:         "method",
+			givenSource:  "package a\nfunc (t T) M() {}\n",
+			expectedName: "T.M",
+		}, {
+			name:         "pointer-method",
+			givenSource:  "package a\nfunc (t *T) M() {}\n",
+			expectedName: "T.M",
+		},
+	}
+
+	for _, spec := range specs {
+		t.Run(spec.name, func(t *testing.T) {
+			astf, err := parser.ParseFile(token.NewFileSet(), spec.name+".go", spec.givenSource, 0)
+			if err != nil {
+				t.Fatalf("got unexpected error: %v", err)
+			}
+			actualName := pkgs.FuncName(astf.Decls[0].(*ast.FuncDecl))
+			if actualName != spec.expectedName {
+				t.Errorf("expected %q, actual %q", spec.expectedName, actualName)
+			}
+		})
+	}
+}